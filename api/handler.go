@@ -0,0 +1,213 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader/openSearch"
+)
+
+// maxSize limits the number of hits per page.
+const maxSize = 100
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// Searcher searches articles, it is implemented by openSearch.Searcher.
+type Searcher interface {
+	Search(q openSearch.Query) (openSearch.Result, error)
+}
+
+// Analyst aggregates entity mentions, it is implemented by openSearch.Searcher.
+type Analyst interface {
+	Mentions(q openSearch.TrendQuery) ([]openSearch.Series, error)
+	Rising(q openSearch.RisingQuery) ([]openSearch.Rising, error)
+	CoOccurrence(q openSearch.CoOccurrenceQuery) (openSearch.CoOccurrence, error)
+}
+
+// Handler serves the HTTP JSON API described by openapi.yaml.
+type Handler struct {
+	searcher Searcher
+	analyst  Analyst
+	log      *zap.SugaredLogger
+	mux      *http.ServeMux
+}
+
+func NewHandler(s Searcher, a Analyst, l *zap.SugaredLogger) *Handler {
+	h := &Handler{searcher: s, analyst: a, log: l, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/search", h.search)
+	h.mux.HandleFunc("/trends/mentions", h.mentions)
+	h.mux.HandleFunc("/trends/rising", h.rising)
+	h.mux.HandleFunc("/trends/cooccurrence", h.coOccurrence)
+	h.mux.HandleFunc("/openapi.yaml", h.openAPI)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type searchResponse struct {
+	openSearch.Result
+	Offset int `json:"offset"`
+	Size   int `json:"size"`
+}
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method=%s not allowed", r.Method))
+		return
+	}
+
+	q, err := parseQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.searcher.Search(q)
+	if err != nil {
+		h.log.Warnw("search error", "method", "search", "query", r.URL.RawQuery, "errMsg", err.Error())
+		h.writeError(w, http.StatusBadGateway, errors.New("could not search articles"))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, searchResponse{Result: res, Offset: q.Offset, Size: q.Size})
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPI)
+}
+
+// parseQuery reads the search parameters documented in openapi.yaml.
+func parseQuery(v url.Values) (openSearch.Query, error) {
+	q := openSearch.Query{
+		Text:    v.Get("q"),
+		Sources: v["source"],
+		Tags:    v["tag"],
+		Pers:    v["per"],
+		Locs:    v["loc"],
+		Orgs:    v["org"],
+	}
+
+	var err error
+	q.Date, q.From, q.To, err = parseRange(v)
+	if err != nil {
+		return q, err
+	}
+
+	switch v.Get("sort") {
+	case "", "relevance":
+		q.Sort = openSearch.Relevance
+	case "newest":
+		q.Sort = openSearch.Newest
+	case "oldest":
+		q.Sort = openSearch.Oldest
+	default:
+		return q, fmt.Errorf("unknown sort=%s", v.Get("sort"))
+	}
+
+	if s := v.Get("originals"); len(s) != 0 {
+		q.Originals, err = strconv.ParseBool(s)
+		if err != nil {
+			return q, fmt.Errorf("originals=%s must be a boolean", s)
+		}
+	}
+
+	if s := v.Get("offset"); len(s) != 0 {
+		q.Offset, err = strconv.Atoi(s)
+		if err != nil || q.Offset < 0 {
+			return q, fmt.Errorf("offset=%s must be a positive integer", s)
+		}
+	}
+	q.Size, err = parseSize(v, 10)
+	if err != nil {
+		return q, err
+	}
+	if q.Offset+q.Size > openSearch.MaxResultWindow {
+		return q, fmt.Errorf("offset+size must not exceed %v", openSearch.MaxResultWindow)
+	}
+
+	return q, nil
+}
+
+// parseRange reads the date field and the optional from and to bounds.
+func parseRange(v url.Values) (openSearch.DateField, time.Time, time.Time, error) {
+	var d openSearch.DateField
+	switch v.Get("date") {
+	case "", "collected":
+		d = openSearch.Collected
+	case "created":
+		d = openSearch.Created
+	default:
+		return d, time.Time{}, time.Time{}, fmt.Errorf("unknown date=%s", v.Get("date"))
+	}
+
+	from, err := parseTime(v.Get("from"), false)
+	if err != nil {
+		return d, time.Time{}, time.Time{}, fmt.Errorf("could not parse from, %w", err)
+	}
+	to, err := parseTime(v.Get("to"), true)
+	if err != nil {
+		return d, time.Time{}, time.Time{}, fmt.Errorf("could not parse to, %w", err)
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return d, time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	return d, from, to, nil
+}
+
+// parseSize reads the size parameter, def if it is missing.
+func parseSize(v url.Values, def int) (int, error) {
+	s := v.Get("size")
+	if len(s) == 0 {
+		return def, nil
+	}
+
+	size, err := strconv.Atoi(s)
+	if err != nil || size < 1 || size > maxSize {
+		return 0, fmt.Errorf("size=%s must be an integer between 1 and %v", s, maxSize)
+	}
+	return size, nil
+}
+
+// parseTime parses RFC3339 timestamps or dates, a date as upper bound includes the whole day.
+func parseTime(s string, end bool) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is neither a RFC3339 timestamp nor a date", s)
+	}
+	if end {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		h.log.Warnw("could not write response", "method", "writeJSON", "errMsg", err.Error())
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader/openSearch"
+)
+
+// fakeOpenSearch answers searches with a single hit and records the last search body.
+func fakeOpenSearch(t *testing.T, status int, body *string) *httptest.Server {
+	return httptest.NewTLSServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if !strings.HasSuffix(r.URL.Path, "/_search") {
+					_, _ = fmt.Fprint(w, `{"version": {"number": "1.2.4", "distribution": "opensearch"}}`)
+					return
+				}
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("could not read search body, %v", err)
+				}
+				*body = string(b)
+
+				w.WriteHeader(status)
+				_, _ = fmt.Fprint(
+					w, `{"hits": {"total": {"value": 42}, "hits": [{"_score": 1.5, "_source": {"id": "article-1", "title": "Wahl"},
+					"highlight": {"title": ["<em>Wahl</em>"]}}]}}`,
+				)
+			},
+		),
+	)
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		status     int
+		wantStatus int
+		wantBody   []string
+	}{
+		{
+			name:       "full text",
+			query:      "q=wahl",
+			status:     200,
+			wantStatus: 200,
+			wantBody:   []string{`"multi_match":{"fields":["title^3","summary^2","body"],"query":"wahl"}`, `"from":0`, `"size":10`},
+		},
+		{
+			name:       "filters",
+			query:      "source=www.tagesschau.de&per=Scholz&org=SPD&tag=Wahl&from=2022-01-01&to=2022-01-31",
+			status:     200,
+			wantStatus: 200,
+			wantBody: []string{
+				`{"prefix":{"url":"https://www.tagesschau.de/"}}`,
+				`{"term":{"tags":"Wahl"}},{"term":{"pers":"Scholz"}},{"term":{"orgs":"SPD"}}`,
+				`{"range":{"collected":{"gte":"2022-01-01T00:00:00Z","lte":"2022-01-31T23:59:59Z"}}}`,
+			},
+		},
+		{
+			name:       "sort and page",
+			query:      "sort=newest&date=created&offset=20&size=5&originals=true",
+			status:     200,
+			wantStatus: 200,
+			wantBody: []string{
+				`"from":20`, `"size":5`, `"sort":[{"created":{"order":"desc"}}`, `"highlight":`,
+				`"must_not":[{"exists":{"field":"duplicateOf"}}]`,
+			},
+		},
+		{
+			name:       "invalid size",
+			query:      "size=1000",
+			wantStatus: 400,
+		},
+		{
+			name:       "invalid range",
+			query:      "from=2022-02-01&to=2022-01-01",
+			wantStatus: 400,
+		},
+		{
+			name:       "invalid sort",
+			query:      "sort=title",
+			wantStatus: 400,
+		},
+		{
+			name:       "opensearch failure",
+			query:      "q=wahl",
+			status:     500,
+			wantStatus: 502,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var body string
+				fake := fakeOpenSearch(t, tt.status, &body)
+				defer fake.Close()
+
+				s, err := openSearch.NewSearcher(
+					openSearch.Config{Addr: strings.TrimPrefix(fake.URL, "https://"), Insecure: true},
+					"articles", zap.NewNop().Sugar(),
+				)
+				if err != nil {
+					t.Fatalf("could not create searcher, %v", err)
+				}
+
+				srv := httptest.NewServer(NewHandler(s, s, zap.NewNop().Sugar()))
+				defer srv.Close()
+
+				resp, err := http.Get(fmt.Sprintf("%s/search?%s", srv.URL, tt.query))
+				if err != nil {
+					t.Fatalf("could not request search, %v", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != tt.wantStatus {
+					t.Fatalf("want status=%v, got %v", tt.wantStatus, resp.StatusCode)
+				}
+				for _, want := range tt.wantBody {
+					if !strings.Contains(body, want) {
+						t.Errorf("want search body to contain %s, got %s", want, body)
+					}
+				}
+				if tt.wantStatus != 200 {
+					return
+				}
+
+				var res searchResponse
+				err = json.NewDecoder(resp.Body).Decode(&res)
+				if err != nil {
+					t.Fatalf("could not decode response, %v", err)
+				}
+				if res.Total != 42 || len(res.Hits) != 1 || res.Hits[0].Article.ID != "article-1" {
+					t.Errorf("want 1 of 42 hits with article-1, got %+v", res)
+				}
+				if res.Hits[0].Highlights["title"][0] != "<em>Wahl</em>" {
+					t.Errorf("want highlighted title, got %v", res.Hits[0].Highlights)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"newsReader/openSearch"
+)
+
+// defaultWindow and defaultBaseline span the windows rising entities are compared in if no bounds are given.
+const (
+	defaultWindow   = 24 * time.Hour
+	defaultBaseline = 7 * 24 * time.Hour
+)
+
+func (h *Handler) mentions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method=%s not allowed", r.Method))
+		return
+	}
+
+	v := r.URL.Query()
+	q := openSearch.TrendQuery{Entities: v["entity"]}
+
+	var err error
+	q.Field, err = parseEntityField(v.Get("type"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q.Date, q.From, q.To, err = parseRange(v)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q.Size, err = parseSize(v, 10)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch i := openSearch.Interval(v.Get("interval")); i {
+	case "":
+		q.Interval = openSearch.Day
+	case openSearch.Hour, openSearch.Day, openSearch.Week:
+		q.Interval = i
+	default:
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("unknown interval=%s", i))
+		return
+	}
+
+	series, err := h.analyst.Mentions(q)
+	if err != nil {
+		h.log.Warnw("mentions error", "method", "mentions", "query", r.URL.RawQuery, "errMsg", err.Error())
+		h.writeError(w, http.StatusBadGateway, errors.New("could not count mentions"))
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"interval": q.Interval, "series": series})
+}
+
+func (h *Handler) rising(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method=%s not allowed", r.Method))
+		return
+	}
+
+	q, err := parseRising(r.URL.Query(), time.Now())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rising, err := h.analyst.Rising(q)
+	if err != nil {
+		h.log.Warnw("rising error", "method", "rising", "query", r.URL.RawQuery, "errMsg", err.Error())
+		h.writeError(w, http.StatusBadGateway, errors.New("could not compute rising entities"))
+		return
+	}
+	h.writeJSON(
+		w, http.StatusOK, map[string]interface{}{
+			"from":         q.From,
+			"to":           q.To,
+			"baselineFrom": q.BaselineFrom,
+			"baselineTo":   q.BaselineTo,
+			"entities":     rising,
+		},
+	)
+}
+
+// parseRising defaults to the day before now compared to the week before that.
+func parseRising(v url.Values, now time.Time) (openSearch.RisingQuery, error) {
+	var q openSearch.RisingQuery
+
+	var err error
+	q.Field, err = parseEntityField(v.Get("type"))
+	if err != nil {
+		return q, err
+	}
+	q.Date, q.From, q.To, err = parseRange(v)
+	if err != nil {
+		return q, err
+	}
+	if q.To.IsZero() {
+		q.To = now
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-defaultWindow)
+	}
+
+	q.BaselineFrom, err = parseTime(v.Get("baseline_from"), false)
+	if err != nil {
+		return q, fmt.Errorf("could not parse baseline_from, %w", err)
+	}
+	q.BaselineTo, err = parseTime(v.Get("baseline_to"), true)
+	if err != nil {
+		return q, fmt.Errorf("could not parse baseline_to, %w", err)
+	}
+	if q.BaselineTo.IsZero() {
+		q.BaselineTo = q.From
+	}
+	if q.BaselineFrom.IsZero() {
+		q.BaselineFrom = q.BaselineTo.Add(-defaultBaseline)
+	}
+	if !q.From.Before(q.To) || !q.BaselineFrom.Before(q.BaselineTo) {
+		return q, errors.New("window and baseline must be non-empty time ranges")
+	}
+
+	q.MinCount = 1
+	if s := v.Get("min_count"); len(s) != 0 {
+		q.MinCount, err = strconv.Atoi(s)
+		if err != nil || q.MinCount < 1 {
+			return q, fmt.Errorf("min_count=%s must be a positive integer", s)
+		}
+	}
+	q.Size, err = parseSize(v, 10)
+	return q, err
+}
+
+func (h *Handler) coOccurrence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method=%s not allowed", r.Method))
+		return
+	}
+
+	v := r.URL.Query()
+	q := openSearch.CoOccurrenceQuery{Entity: v.Get("entity")}
+	if len(q.Entity) == 0 {
+		h.writeError(w, http.StatusBadRequest, errors.New("entity must not be empty"))
+		return
+	}
+
+	var err error
+	q.Field, err = parseEntityField(v.Get("type"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, s := range v["with"] {
+		f, err := parseEntityField(s)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q.With = append(q.With, f)
+	}
+	q.Date, q.From, q.To, err = parseRange(v)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q.Size, err = parseSize(v, 10)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	co, err := h.analyst.CoOccurrence(q)
+	if err != nil {
+		h.log.Warnw("co-occurrence error", "method", "coOccurrence", "query", r.URL.RawQuery, "errMsg", err.Error())
+		h.writeError(w, http.StatusBadGateway, errors.New("could not count co-occurrences"))
+		return
+	}
+	h.writeJSON(w, http.StatusOK, co)
+}
+
+// parseEntityField maps the entity type parameter to its article field, persons by default.
+func parseEntityField(s string) (openSearch.EntityField, error) {
+	switch s {
+	case "", "per":
+		return openSearch.Pers, nil
+	case "loc":
+		return openSearch.Locs, nil
+	case "org":
+		return openSearch.Orgs, nil
+	case "tag":
+		return openSearch.Tags, nil
+	default:
+		return "", fmt.Errorf("unknown entity type=%s", s)
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader/openSearch"
+)
+
+type analyst struct {
+	co openSearch.CoOccurrenceQuery
+}
+
+func (a *analyst) Mentions(openSearch.TrendQuery) ([]openSearch.Series, error) {
+	return nil, nil
+}
+
+func (a *analyst) Rising(openSearch.RisingQuery) ([]openSearch.Rising, error) {
+	return nil, nil
+}
+
+func (a *analyst) CoOccurrence(q openSearch.CoOccurrenceQuery) (openSearch.CoOccurrence, error) {
+	a.co = q
+	return openSearch.CoOccurrence{Entity: q.Entity}, nil
+}
+
+func TestParseRising(t *testing.T) {
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		query            string
+		wantFrom, wantBF time.Time
+		wantErr          bool
+	}{
+		{
+			name:     "default windows",
+			query:    "type=org",
+			wantFrom: now.Add(-24 * time.Hour),
+			wantBF:   now.Add(-8 * 24 * time.Hour),
+		},
+		{
+			name:     "explicit windows",
+			query:    "from=2022-01-09&to=2022-01-09&baseline_from=2022-01-01&baseline_to=2022-01-08",
+			wantFrom: time.Date(2022, 1, 9, 0, 0, 0, 0, time.UTC),
+			wantBF:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "empty baseline",
+			query:   "baseline_from=2022-01-08&baseline_to=2022-01-01",
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			query:   "type=event",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				v, _ := url.ParseQuery(tt.query)
+				q, err := parseRising(v, now)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("want error=%v, got %v", tt.wantErr, err)
+				}
+				if tt.wantErr {
+					return
+				}
+				if !q.From.Equal(tt.wantFrom) || !q.BaselineFrom.Equal(tt.wantBF) {
+					t.Errorf("want from=%v baselineFrom=%v, got %v %v", tt.wantFrom, tt.wantBF, q.From, q.BaselineFrom)
+				}
+			},
+		)
+	}
+}
+
+func TestCoOccurrence(t *testing.T) {
+	a := &analyst{}
+	srv := httptest.NewServer(NewHandler(nil, a, zap.NewNop().Sugar()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/trends/cooccurrence?type=per&entity=Scholz&with=org&with=loc&size=5")
+	if err != nil {
+		t.Fatalf("could not request co-occurrence, %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("want status 200, got %v", resp.StatusCode)
+	}
+	if a.co.Field != openSearch.Pers || a.co.Entity != "Scholz" || a.co.Size != 5 || len(a.co.With) != 2 ||
+		a.co.With[0] != openSearch.Orgs || a.co.With[1] != openSearch.Locs {
+		t.Errorf("want co-occurrence of per Scholz with orgs and locs, got %+v", a.co)
+	}
+
+	resp, err = http.Get(srv.URL + "/trends/cooccurrence?type=per")
+	if err != nil {
+		t.Fatalf("could not request co-occurrence, %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Errorf("want status 400 without entity, got %v", resp.StatusCode)
+	}
+}
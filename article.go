@@ -7,18 +7,40 @@ import (
 )
 
 type Article struct {
-	ID        string   `json:"id"`
-	Author    string   `json:"author"`
-	Body      string   `json:"body"`
-	Title     string   `json:"title"`
-	Created   string   `json:"created"`
-	Collected string   `json:"collected"`
-	Url       string   `json:"url"`
-	Summary   string   `json:"summary"`
-	Tags      []string `json:"tags"`
-	Pers      []string `json:"pers"`
-	Locs      []string `json:"locs"`
-	Orgs      []string `json:"orgs"`
+	ID        string `json:"id"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	Title     string `json:"title"`
+	Created   string `json:"created"`
+	Collected string `json:"collected"`
+	Url       string `json:"url"`
+	Summary   string `json:"summary"`
+	// SummaryMethod is the method which produced Summary, AbstractiveSummary or ExtractiveSummary.
+	SummaryMethod string   `json:"summaryMethod,omitempty"`
+	Tags          []string `json:"tags"`
+	Pers          []string `json:"pers"`
+	Locs          []string `json:"locs"`
+	Orgs          []string `json:"orgs"`
+	Keywords      []string `json:"keywords,omitempty"`
+	Story         string   `json:"story,omitempty"`
+	// DuplicateOf is the ID of the earliest article the article is a near-duplicate of.
+	DuplicateOf string  `json:"duplicateOf,omitempty"`
+	Similarity  float64 `json:"similarity,omitempty"`
+	// ProcessingErrors are the failures of the processors whose fields are missing.
+	ProcessingErrors []ProcessingError `json:"processingErrors,omitempty"`
+
+	// Version is the number of events in the article stream when the article was read.
+	// A zero Version disables the optimistic concurrency check on publish.
+	Version uint64 `json:"-"`
+	// Meta is the metadata of the event the article was read from.
+	Meta Metadata `json:"-"`
+}
+
+// ProcessingError is the failure of a Processor on an article.
+type ProcessingError struct {
+	Processor string `json:"processor"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error"`
 }
 
 func ArticleID(a Article) string {
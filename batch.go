@@ -0,0 +1,75 @@
+package newsReader
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// PublishError reports an article which failed to be published in the background.
+type PublishError struct {
+	Article Article
+	Err     error
+}
+
+// BatchPublisher is a Publisher buffering articles. Failures of buffered articles are reported on
+// the channel returned by Failures, which is closed by Close after all buffered articles are flushed.
+type BatchPublisher interface {
+	Publisher
+	Failures() <-chan PublishError
+	Close() error
+}
+
+// maxErrors bounds the errors a long running Operator keeps until it returns, all errors are logged
+// when they occur.
+const maxErrors = 100
+
+// errorList keeps the first maxErrors errors and counts the others.
+type errorList struct {
+	ee      []error
+	dropped int
+}
+
+func (l *errorList) add(err error) {
+	if len(l.ee) < maxErrors {
+		l.ee = append(l.ee, err)
+		return
+	}
+	l.dropped++
+}
+
+func (l errorList) errors() []error {
+	if l.dropped == 0 {
+		return l.ee
+	}
+	return append(l.ee, fmt.Errorf("%d more errors", l.dropped))
+}
+
+// watch logs the failures of pub if it is a BatchPublisher. The returned func closes pub and returns
+// the first maxErrors failures.
+func watch(pub Publisher, log *zap.SugaredLogger) func() []error {
+	bp, ok := pub.(BatchPublisher)
+	if !ok {
+		return func() []error { return nil }
+	}
+
+	var ee errorList
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for f := range bp.Failures() {
+			log.Warnw("publish error", "method", "watch", "articleID", f.Article.ID, "errMsg", f.Err.Error())
+			ee.add(fmt.Errorf("publish article with ID=%v failed, %w", f.Article.ID, f.Err))
+		}
+	}()
+
+	return func() []error {
+		err := bp.Close()
+		<-done
+		errs := ee.errors()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not close publisher, %w", err))
+		}
+		return errs
+	}
+}
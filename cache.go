@@ -0,0 +1,93 @@
+package newsReader
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ResultCache persists the outputs of processors by key. Load returns ErrNotFound for unknown keys.
+type ResultCache interface {
+	Load(key string) (Article, error)
+	Save(key string, a Article) error
+}
+
+// Cached is a Processor reusing the outputs of a processor for articles whose read fields have been
+// processed by the same version before, e.g. recollected articles with an unchanged body. Only
+// deterministic processors without side effects may be cached, e.g. Summary and NER but not a Clusterer.
+type Cached struct {
+	p     Processor
+	cache ResultCache
+	log   *zap.SugaredLogger
+}
+
+// NewCached returns an error if p reports no version, its results would be reused after model upgrades.
+func NewCached(p Processor, cache ResultCache, l *zap.SugaredLogger) (*Cached, error) {
+	if len(version(p)) == 0 {
+		return nil, fmt.Errorf("could not cache processor=%s without version", p.Name())
+	}
+	return &Cached{p: p, cache: cache, log: l}, nil
+}
+
+func (c Cached) Name() string {
+	return c.p.Name()
+}
+
+func (c Cached) Version() string {
+	return version(c.p)
+}
+
+func (c Cached) Reads() []Field {
+	return reads(c.p)
+}
+
+func (c Cached) Writes() []Field {
+	return writes(c.p)
+}
+
+// Process returns a with the cached fields written by the processor, or processes and caches a on a miss.
+// Cache failures are logged, the article is processed without the cache.
+func (c Cached) Process(a Article) (Article, error) {
+	key, err := c.key(a)
+	if err != nil {
+		return a, err
+	}
+
+	cached, err := c.cache.Load(key)
+	if err == nil {
+		c.log.Debugw("cache hit", "method", "Process", "articleID", a.ID, "processorName", c.Name())
+		for _, f := range c.Writes() {
+			copyField(&a, cached, f)
+		}
+		return a, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		c.log.Warnw("could not load cached result", "method", "Process", "articleID", a.ID, "errMsg", err.Error())
+	}
+
+	res, err := c.p.Process(a)
+	if err != nil {
+		return res, err
+	}
+
+	err = c.cache.Save(key, res)
+	if err != nil {
+		c.log.Warnw("could not save result", "method", "Process", "articleID", a.ID, "errMsg", err.Error())
+	}
+	return res, nil
+}
+
+// key hashes the name and version of the processor and the fields of a it reads.
+func (c Cached) key(a Article) (string, error) {
+	b, err := input(a, c.Reads())
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00", c.Name(), c.Version())
+	_, _ = h.Write(b)
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
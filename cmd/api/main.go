@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"newsReader/api"
+	"newsReader/openSearch"
+)
+
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	debug := flag.Bool("debug", false, "set loglevel to debug")
+	env := flag.String("env-file", "./conf/.env", "set path to env-file")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	index := flag.String("index", "articles", "index or alias to search")
+	flag.Parse()
+
+	cfg := zap.NewProductionConfig()
+	if *debug {
+		cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+	zapper, err := cfg.Build()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "could init logger, %v\n", err.Error())
+		os.Exit(1)
+	}
+
+	log := zapper.Sugar()
+
+	err = godotenv.Load(*env)
+	if err != nil {
+		log.Fatalf("could load env-file=%s, %v\n", *env, err.Error())
+	}
+
+	osCfg, err := openSearch.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read opensearch config from .env, %v\n", err)
+	}
+
+	searcher, err := openSearch.NewSearcher(osCfg, *index, log.Named("searcher-openSearch"))
+	if err != nil {
+		log.Fatalf("could not create new openSearch searcher, %v\n", err.Error())
+	}
+
+	srv := &http.Server{
+		Addr:         *addr,
+		Handler:      api.NewHandler(searcher, searcher, log.Named("api")),
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 30,
+	}
+
+	log.Infow("serve api", "addr", *addr, "index", *index, "version", version)
+	err = srv.ListenAndServe()
+	if err != nil {
+		log.Fatalf("api finished with error, %v\n", err)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -12,6 +15,9 @@ import (
 	"newsReader/openSearch"
 )
 
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	debug := flag.Bool("debug", false, "set loglevel to debug")
 	env := flag.String("env-file", "./conf/.env", "set path to env-file")
@@ -47,33 +53,37 @@ func main() {
 		log.Fatal("could not read eventstore addr from .env")
 	}
 
-	queue, err := eventStore.NewQueue(esUser, esPwd, esAddr, log.Named("queue"))
+	queue, err := eventStore.NewQueue(
+		esUser, esPwd, esAddr,
+		eventStore.Producer{Service: "archiver", Version: version},
+		log.Named("queue"),
+	)
 	if err != nil {
 		log.Fatalf("could not create eventStore, %v\n", err.Error())
 	}
 	con := eventStore.NewConsumer(queue, "preprocessed", log.Named("consumer-preprocessed"))
 
-	osUser, ok := os.LookupEnv("OS_USER")
-	if !ok {
-		log.Fatal("could not read opensearch user from .env")
-	}
-	osPwd, ok := os.LookupEnv("OS_PWD")
-	if !ok {
-		log.Fatal("could not read opensearch pwd from .env")
+	osCfg, err := openSearch.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read opensearch config from .env, %v\n", err)
 	}
-	osAddr, ok := os.LookupEnv("OS_ADDR")
-	if !ok {
-		log.Fatal("could not read opensearch addr from .env")
+
+	// optional rollover, e.g. OS_INDEX=articles-1 OS_INDEX_LAYOUT=2006.01 OS_INDEX_DATE=created OS_ALIAS=articles
+	index, err := openSearch.IndexFromEnv()
+	if err != nil {
+		log.Fatalf("could not read opensearch index from .env, %v\n", err)
 	}
 
-	pub, err := openSearch.NewPublisher(osUser, osPwd, osAddr, log.Named("publisher-openSearch"))
+	pub, err := openSearch.NewPublisher(osCfg, index, log.Named("publisher-openSearch"))
 	if err != nil {
 		log.Fatalf("could not create new openSearch publisher, %v\n", err.Error())
 	}
 
+	bulk := openSearch.NewBulkPublisher(pub, 500, 5<<20, time.Second*5)
+
 	ab := newsReader.NewOperatorBuilder()
 	archiver, err := ab.Consumer(con).
-		Publisher(pub).
+		Publisher(bulk).
 		NumWorker(2).
 		Logger(log.Named("operator")).
 		Build()
@@ -81,11 +91,18 @@ func main() {
 		log.Fatalf("could not build archiver, %v\n", err)
 	}
 
-	err = archiver.Run()
+	// flush the buffered articles on shutdown
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Infow("shut down", "signal", sig.String())
+		close(stop)
+	}()
 
+	err = archiver.RunUntil(stop)
 	if err != nil {
 		log.Fatalf("archiver finished with error, %v\n", err)
-		os.Exit(1)
 	}
-
 }
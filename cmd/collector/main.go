@@ -4,19 +4,44 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 	"newsReader"
 	"newsReader/colly"
+	"newsReader/disk"
 	"newsReader/eventStore"
+	"newsReader/memory"
 )
 
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	debug := flag.Bool("debug", false, "set loglevel to debug")
 	env := flag.String("env-file", "./conf/.env", "set path to env-file")
+	timing := flag.String("schedule", "2h", "interval or cron expression of crawls")
+	jitter := flag.Duration("jitter", 0, "max random delay of scheduled crawls")
+	hours := flag.String("active-hours", "", "hours of day to crawl in, e.g. 6-22, always if empty")
+	tz := flag.String("tz", "Europe/Berlin", "time zone of the schedule and active hours")
+	stopAfter := flag.Duration("stop", time.Hour*48, "stop collecting after, never if 0")
+	crawl := colly.DefaultConfig()
+	flag.StringVar(&crawl.UserAgent, "user-agent", crawl.UserAgent, "user agent of crawl requests")
+	ignoreRobots := flag.Bool("ignore-robots", false, "do not respect robots.txt")
+	flag.IntVar(&crawl.MaxDepth, "max-depth", crawl.MaxDepth, "max link depth of crawls, unlimited if 0")
+	flag.DurationVar(&crawl.Delay, "crawl-delay", crawl.Delay, "min delay between requests to a domain")
+	flag.DurationVar(&crawl.RandomDelay, "crawl-random-delay", crawl.RandomDelay, "max random delay added to crawl-delay")
+	flag.IntVar(&crawl.Parallelism, "crawl-parallelism", crawl.Parallelism, "max concurrent requests to a domain")
+	flag.DurationVar(&crawl.Timeout, "request-timeout", crawl.Timeout, "timeout of crawl requests")
+	cacheDir := flag.String("cache-dir", "", "dir of the on-disk cache of fetched pages, in-memory if empty")
+	cacheSize := flag.Int("cache-size", 10000, "max number of pages of the in-memory cache, no cache if 0")
+	flag.BoolVar(&crawl.CacheBodies, "cache-bodies", false, "cache page bodies to parse unchanged pages again")
 	flag.Parse()
+	crawl.RespectRobots = !*ignoreRobots
 
 	cfg := zap.NewProductionConfig()
 	if *debug {
@@ -48,36 +73,81 @@ func main() {
 		log.Fatal("could not read eventstore addr from .env")
 	}
 
-	queue, err := eventStore.NewQueue(usr, pwd, addr, log.Named("queue"))
+	queue, err := eventStore.NewQueue(
+		usr, pwd, addr,
+		eventStore.Producer{Service: "collector", Version: version},
+		log.Named("queue"),
+	)
 	if err != nil {
 		log.Fatalf("could not create eventStore, %v\n", err.Error())
 	}
 
-	c := colly.NewTagesschauCrawler(log.Named("tagesschau"))
+	switch {
+	case len(*cacheDir) != 0:
+		crawl.Pages, err = disk.NewPageCache(*cacheDir)
+		if err != nil {
+			log.Fatalf("could not init page cache, %v\n", err)
+		}
+	case *cacheSize > 0:
+		crawl.Pages = memory.NewPageCache(*cacheSize)
+	}
+
+	c := colly.NewTagesschauCrawler(crawl, log.Named("tagesschau"))
 	p := eventStore.NewPublisher(queue, "collected", log.Named("publisher-collected"))
 
+	t, err := newsReader.ParseTiming(*timing)
+	if err != nil {
+		log.Fatalf("could not parse schedule, %v\n", err)
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		log.Fatalf("could not load time zone=%s, %v\n", *tz, err)
+	}
+	schedule := newsReader.Schedule{Timing: t, Jitter: *jitter, Location: loc}
+	if len(*hours) != 0 {
+		schedule.ActiveFrom, schedule.ActiveTo, err = newsReader.ParseHours(*hours)
+		if err != nil {
+			log.Fatalf("could not parse active hours, %v\n", err)
+		}
+	}
+
 	cb := newsReader.NewCollectorBuilder()
-	collector, err := cb.Crawlers(c).Publisher(p).NumWorker(1).Logger(log.Named("collector")).Build()
+	collector, err := cb.StreamCrawlers(c).
+		Schedule(c.Name(), schedule).
+		OnReport(
+			func(r newsReader.CrawlReport) {
+				// errors are logged by the collector, a crawl without any article needs attention
+				if r.Published == 0 && len(r.Errors) != 0 {
+					log.Errorw("crawl failed", "resource", r.Crawler, "numErrors", len(r.Errors), "firstErr", r.Errors[0].Error())
+				}
+			},
+		).
+		Publisher(p).
+		NumWorker(1).
+		Logger(log.Named("collector")).
+		Build()
 	if err != nil {
 		log.Fatalf("could not build collector, %v\n", err.Error())
 	}
 
-	ticker := time.NewTicker(time.Hour * 2)
-	stop := time.After(time.Hour * 48)
-
-	for {
-		// start immediately
-		err = collector.RunOnce()
-		if err != nil {
-			log.Fatalf("collector finished with error, %v\n", err)
-		}
+	stop := make(chan struct{})
+	if *stopAfter > 0 {
+		time.AfterFunc(*stopAfter, func() { close(stop) })
+	}
 
-		select {
-		case <-ticker.C:
-			continue
-		case <-stop:
-			os.Exit(0)
+	// crawl immediately and on demand on SIGHUP
+	triggers := make(chan os.Signal, 1)
+	signal.Notify(triggers, syscall.SIGHUP)
+	triggers <- syscall.SIGHUP
+	go func() {
+		for range triggers {
+			log.Infow("trigger crawl", "resource", c.Name())
+			err := collector.Trigger(c.Name())
+			if err != nil {
+				log.Errorw("could not trigger crawl", "resource", c.Name(), "errMsg", err)
+			}
 		}
-	}
+	}()
 
+	collector.Run(stop)
 }
@@ -4,18 +4,34 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 	"newsReader"
+	"newsReader/disk"
 	"newsReader/eventStore"
+	"newsReader/memory"
 	"newsReader/tsClient"
 )
 
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	debug := flag.Bool("debug", false, "set loglevel to debug")
 	env := flag.String("env-file", "./conf/.env", "set path to env-file")
+	storyThreshold := flag.Float64("story-threshold", 0.3, "min similarity of an article to join a story")
+	numKeywords := flag.Int("keywords", 8, "number of keyphrases per article")
+	numSentences := flag.Int("summary-sentences", 3, "sentences of extractive summaries if torchServe fails, 0 disables the fallback")
+	dupDistance := flag.Int("duplicate-distance", 6, "max differing fingerprint bits of near-duplicates")
+	minSummary := flag.Int("summary-min-length", 300, "min characters of bodies to summarize")
+	germanOnly := flag.Bool("german-only", false, "skip summary and ner of articles which are not German")
+	required := flag.String("required", "NER", "comma separated processors whose failure dead-letters articles")
+	cacheDir := flag.String("cache-dir", "", "dir of the on-disk cache of summary and ner results, in-memory if empty")
+	cacheSize := flag.Int("cache-size", 10000, "max results of the in-memory cache")
+	storyWindow := flag.Duration("story-window", time.Hour*48, "time a story accepts new articles after its latest")
 	flag.Parse()
 
 	cfg := zap.NewProductionConfig()
@@ -48,7 +64,11 @@ func main() {
 		log.Fatal("could not read eventstore addr from .env")
 	}
 
-	queue, err := eventStore.NewQueue(usr, pwd, esAddr, log.Named("queue"))
+	queue, err := eventStore.NewQueue(
+		usr, pwd, esAddr,
+		eventStore.Producer{Service: "preprocessor", Version: version},
+		log.Named("queue"),
+	)
 	if err != nil {
 		log.Fatalf("could not create eventStore, %v\n", err.Error())
 	}
@@ -58,23 +78,77 @@ func main() {
 		log.Fatal("could not read torchServe addr from .env")
 	}
 
-	summary, err := tsClient.NewSummary(tsAddr, log.Named("summary"), time.Minute*2)
+	summaryVersion, ok := os.LookupEnv("TS_SUMMARY_VERSION")
+	if !ok {
+		log.Fatal("could not read summary model version from .env")
+	}
+	nerVersion, ok := os.LookupEnv("TS_NER_VERSION")
+	if !ok {
+		log.Fatal("could not read ner model version from .env")
+	}
+
+	var cache newsReader.ResultCache = memory.NewResultCache(*cacheSize)
+	if len(*cacheDir) != 0 {
+		cache, err = disk.NewResultCache(*cacheDir)
+		if err != nil {
+			log.Fatalf("could not init cache, %v\n", err)
+		}
+	}
+
+	abstractive, err := tsClient.NewSummary(tsAddr, summaryVersion, log.Named("summary"), time.Minute*2)
 	if err != nil {
 		log.Fatalf("could not init summary, %v\n", err.Error())
 	}
-	ner, err := tsClient.NewNER(tsAddr, log.Named("ner"), time.Second*30)
+	cachedSummary, err := newsReader.NewCached(abstractive, cache, log.Named("cache-summary"))
+	if err != nil {
+		log.Fatalf("could not init summary cache, %v\n", err)
+	}
+	var summary newsReader.Processor = cachedSummary
+	if *numSentences > 0 {
+		extractive := newsReader.NewTextRank(*numSentences, log.Named("textrank"))
+		summary = newsReader.NewFallback(log.Named("summary-fallback"), summary, extractive)
+	}
+	tsNER, err := tsClient.NewNER(tsAddr, nerVersion, log.Named("ner"), time.Second*30)
 	if err != nil {
 		log.Fatalf("could not init summary, %v\n", err.Error())
 	}
+	ner, err := newsReader.NewCached(tsNER, cache, log.Named("cache-ner"))
+	if err != nil {
+		log.Fatalf("could not init ner cache, %v\n", err)
+	}
 
 	con := eventStore.NewConsumer(queue, "collected", log.Named("consumer-collected"))
 	pub := eventStore.NewPublisher(queue, "preprocessed", log.Named("publisher-preprocessed"))
+	dead := eventStore.NewPublisher(queue, "failed", log.Named("publisher-failed"))
 
-	ob := newsReader.NewOperatorBuilder()
+	keywords := newsReader.NewKeywordExtractor(*numKeywords, log.Named("keywords"))
+	dedup := newsReader.NewDeduplicator(memory.NewFingerprintStore(), *dupDistance, log.Named("duplicate"))
+	stories := newsReader.NewClusterer(queue, *storyThreshold, *storyWindow, log.Named("story"))
+
+	// the in-memory state of all three processors is restored from one replay
+	src := eventStore.NewConsumer(queue, "preprocessed", log.Named("source-preprocessed"))
+	err = newsReader.Warm(src, log.Named("warm"), keywords, dedup, stories)
+	if err != nil {
+		log.Fatalf("could not warm processors, %v\n", err)
+	}
+
+	ob := newsReader.NewOperatorBuilder().When(summary.Name(), newsReader.MinBodyLength(*minSummary))
+	if *germanOnly {
+		ob.When(summary.Name(), newsReader.German()).When(ner.Name(), newsReader.German())
+	}
+	for _, name := range strings.Split(*required, ",") {
+		if name = strings.TrimSpace(name); len(name) != 0 {
+			ob.OnFailure(name, newsReader.Required)
+		}
+	}
 	preprocessor, err := ob.Consumer(con).
-		Publisher(pub).
+		Publisher(stories.Publisher(pub)).
+		Reader(con).
+		Retries(2, time.Second*10).
+		DeadLetter(dead).
+		OnConflict(newsReader.RetryOnConflict).
 		NumWorker(2).
-		Processors(summary, ner).
+		Processors(summary, ner, keywords, dedup, stories).
 		Logger(log.Named("operator")).
 		Build()
 	if err != nil {
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/eventStore"
+	"newsReader/openSearch"
+)
+
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	debug := flag.Bool("debug", false, "set loglevel to debug")
+	env := flag.String("env-file", "./conf/.env", "set path to env-file")
+	eType := flag.String("event-type", "preprocessed", "event type to replay")
+	rebuild := flag.String("index", "", "rebuild into new indices of this name and swap the alias to them")
+	latest := flag.Bool("latest", false, "replay only the latest state per article stream")
+	rate := flag.Int("rate", 50, "max published articles per second, < 1 disables the limit")
+	flag.Parse()
+
+	cfg := zap.NewProductionConfig()
+	if *debug {
+		cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+	zapper, err := cfg.Build()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "could init logger, %v\n", err.Error())
+		os.Exit(1)
+	}
+
+	log := zapper.Sugar()
+
+	err = godotenv.Load(*env)
+	if err != nil {
+		log.Fatalf("could load env-file=%s, %v\n", *env, err.Error())
+	}
+
+	esUser, ok := os.LookupEnv("ES_USER")
+	if !ok {
+		log.Fatal("could not read eventstore user from .env")
+	}
+	esPwd, ok := os.LookupEnv("ES_PWD")
+	if !ok {
+		log.Fatal("could not read eventstore pwd from .env")
+	}
+	esAddr, ok := os.LookupEnv("ES_ADDR")
+	if !ok {
+		log.Fatal("could not read eventstore addr from .env")
+	}
+
+	queue, err := eventStore.NewQueue(
+		esUser, esPwd, esAddr,
+		eventStore.Producer{Service: "replay", Version: version},
+		log.Named("queue"),
+	)
+	if err != nil {
+		log.Fatalf("could not create eventStore, %v\n", err.Error())
+	}
+	src := eventStore.NewConsumer(queue, *eType, log.Named(fmt.Sprintf("source-%s", *eType)))
+
+	osCfg, err := openSearch.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("could not read opensearch config from .env, %v\n", err)
+	}
+
+	// replays write into the indices of the archiver, stale events are skipped by their version
+	index, err := openSearch.IndexFromEnv()
+	if err != nil {
+		log.Fatalf("could not read opensearch index from .env, %v\n", err)
+	}
+	alias := index.Alias()
+	if len(*rebuild) != 0 {
+		if len(alias) == 0 {
+			log.Fatal("could not rebuild index without an alias to swap")
+		}
+		index = index.Rebuild(*rebuild)
+	}
+
+	pub, err := openSearch.NewPublisher(osCfg, index, log.Named("publisher-openSearch"))
+	if err != nil {
+		log.Fatalf("could not create new openSearch publisher, %v\n", err.Error())
+	}
+
+	bulk := openSearch.NewBulkPublisher(pub, 500, 5<<20, time.Second*5)
+
+	rb := newsReader.NewReplayerBuilder()
+	replayer, err := rb.Source(src).
+		Publisher(bulk).
+		Rate(*rate).
+		Progress(500).
+		LatestOnly(*latest).
+		Logger(log.Named("replayer")).
+		Build()
+	if err != nil {
+		log.Fatalf("could not build replayer, %v\n", err)
+	}
+
+	n, err := replayer.Run()
+	if err != nil {
+		log.Fatalf("replay finished with error, %v\n", err)
+	}
+	if len(*rebuild) == 0 {
+		log.Infow("replayed into archiver index", "alias", alias, "numArticles", n)
+		return
+	}
+
+	// the archiver keeps writing into its indices until it is restarted with OS_INDEX set to the rebuilt name
+	err = pub.SwapAlias(alias)
+	if err != nil {
+		log.Fatalf("could not swap alias=%s to index=%s, %v\n", alias, *rebuild, err)
+	}
+
+	log.Infow("rebuilt index", "index", *rebuild, "alias", alias, "numArticles", n)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/disk"
+	"newsReader/eventStore"
+	"newsReader/tsClient"
+)
+
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	debug := flag.Bool("debug", false, "set loglevel to debug")
+	env := flag.String("env-file", "./conf/.env", "set path to env-file")
+	from := flag.String("from", "", "reprocess articles collected since, RFC3339")
+	to := flag.String("to", "", "reprocess articles collected before, RFC3339")
+	host := flag.String("host", "", "reprocess articles from host only")
+	procs := flag.String("processors", "Summary,NER", "comma separated processors to apply")
+	cacheDir := flag.String("cache-dir", "", "dir of the on-disk cache of summary and ner results shared with the preprocessor")
+	flag.Parse()
+
+	cfg := zap.NewProductionConfig()
+	if *debug {
+		cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+	zapper, err := cfg.Build()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "could init logger, %v\n", err.Error())
+		os.Exit(1)
+	}
+
+	log := zapper.Sugar()
+
+	err = godotenv.Load(*env)
+	if err != nil {
+		log.Fatalf("could load env-file=%s, %v\n", *env, err.Error())
+	}
+
+	var filters []newsReader.Filter
+	fromT, err := parseTime(*from)
+	if err != nil {
+		log.Fatalf("could not parse from=%s, %v\n", *from, err)
+	}
+	toT, err := parseTime(*to)
+	if err != nil {
+		log.Fatalf("could not parse to=%s, %v\n", *to, err)
+	}
+	filters = append(filters, newsReader.Between(fromT, toT))
+	if len(*host) != 0 {
+		filters = append(filters, newsReader.FromHost(*host))
+	}
+
+	usr, ok := os.LookupEnv("ES_USER")
+	if !ok {
+		log.Fatal("could not read eventstore user from .env")
+	}
+	pwd, ok := os.LookupEnv("ES_PWD")
+	if !ok {
+		log.Fatal("could not read eventstore pwd from .env")
+	}
+	esAddr, ok := os.LookupEnv("ES_ADDR")
+	if !ok {
+		log.Fatal("could not read eventstore addr from .env")
+	}
+
+	queue, err := eventStore.NewQueue(
+		usr, pwd, esAddr,
+		eventStore.Producer{Service: "reprocess", Version: version},
+		log.Named("queue"),
+	)
+	if err != nil {
+		log.Fatalf("could not create eventStore, %v\n", err.Error())
+	}
+
+	tsAddr, ok := os.LookupEnv("TS_ADDR")
+	if !ok {
+		log.Fatal("could not read torchServe addr from .env")
+	}
+
+	summaryVersion, ok := os.LookupEnv("TS_SUMMARY_VERSION")
+	if !ok {
+		log.Fatal("could not read summary model version from .env")
+	}
+	nerVersion, ok := os.LookupEnv("TS_NER_VERSION")
+	if !ok {
+		log.Fatal("could not read ner model version from .env")
+	}
+
+	summary, err := tsClient.NewSummary(tsAddr, summaryVersion, log.Named("summary"), time.Minute*2)
+	if err != nil {
+		log.Fatalf("could not init summary, %v\n", err.Error())
+	}
+	ner, err := tsClient.NewNER(tsAddr, nerVersion, log.Named("ner"), time.Second*30)
+	if err != nil {
+		log.Fatalf("could not init ner, %v\n", err.Error())
+	}
+
+	var abstractive, entities newsReader.Processor = summary, ner
+	if len(*cacheDir) != 0 {
+		cache, err := disk.NewResultCache(*cacheDir)
+		if err != nil {
+			log.Fatalf("could not init cache, %v\n", err)
+		}
+		abstractive, err = newsReader.NewCached(summary, cache, log.Named("cache-summary"))
+		if err != nil {
+			log.Fatalf("could not init summary cache, %v\n", err)
+		}
+		entities, err = newsReader.NewCached(ner, cache, log.Named("cache-ner"))
+		if err != nil {
+			log.Fatalf("could not init ner cache, %v\n", err)
+		}
+	}
+
+	summaries := newsReader.NewFallback(log.Named("summary-fallback"), abstractive, newsReader.NewTextRank(3, log.Named("textrank")))
+	available := map[string]newsReader.Processor{summaries.Name(): summaries, entities.Name(): entities}
+	var pp []newsReader.Processor
+	for _, name := range strings.Split(*procs, ",") {
+		p, ok := available[strings.TrimSpace(name)]
+		if !ok {
+			log.Fatalf("unknown processor=%s\n", name)
+		}
+		pp = append(pp, p)
+	}
+
+	src := eventStore.NewConsumer(queue, "collected", log.Named("source-collected"))
+	// reprocessing starts from the preprocessed articles to keep the results of the processors not applied
+	state := eventStore.NewConsumer(queue, "preprocessed", log.Named("state-preprocessed"))
+	con := newsReader.NewReplayConsumer(src, state, log.Named("consumer-replay"), filters...)
+	pub := eventStore.NewPublisher(queue, "preprocessed", log.Named("publisher-preprocessed"))
+
+	ob := newsReader.NewOperatorBuilder()
+	reprocessor, err := ob.Consumer(con).
+		Publisher(pub).
+		NumWorker(2).
+		Processors(pp...).
+		Logger(log.Named("operator")).
+		Build()
+	if err != nil {
+		log.Fatalf("could not build reprocessor, %v\n", err)
+	}
+
+	err = reprocessor.Run()
+	if err != nil {
+		log.Fatalf("reprocessor finished with error, %v\n", err)
+	}
+	err = con.Err()
+	if err != nil {
+		log.Fatalf("replay finished with error, %v\n", err)
+	}
+}
+
+func parseTime(s string) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
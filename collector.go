@@ -4,32 +4,61 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 )
 
 type Collector struct {
-	crawlers  []Crawler
+	crawlers  []StreamCrawler
+	schedules map[string]Schedule
 	pub       Publisher
 	log       *zap.SugaredLogger
-	tasks     chan Crawler
 	numWorker int
+	// triggers request an immediate run of a crawler, busy is held while a crawler runs
+	triggers map[string]chan struct{}
+	busy     map[string]chan struct{}
+	onReport func(r CrawlReport)
 }
 
 func NewCollectorBuilder() *CollectorBuilder {
-	return &CollectorBuilder{}
+	return &CollectorBuilder{ss: make(map[string]Schedule)}
 }
 
 type CollectorBuilder struct {
-	cc []Crawler
+	cc []StreamCrawler
+	ss map[string]Schedule
 	p  Publisher
 	l  *zap.SugaredLogger
 	n  int
+	r  func(r CrawlReport)
 }
 
+// Crawlers adds crawlers which return all articles at the end of their crawl.
 func (b *CollectorBuilder) Crawlers(cc ...Crawler) *CollectorBuilder {
-	b.cc = cc
+	for _, c := range cc {
+		b.cc = append(b.cc, Stream(c))
+	}
+	return b
+}
+
+// StreamCrawlers adds crawlers whose articles are published while they crawl.
+func (b *CollectorBuilder) StreamCrawlers(cc ...StreamCrawler) *CollectorBuilder {
+	b.cc = append(b.cc, cc...)
+	return b
+}
+
+// Schedule sets the schedule of the crawler with name for Run.
+func (b *CollectorBuilder) Schedule(name string, s Schedule) *CollectorBuilder {
+	b.ss[name] = s
+	return b
+}
+
+// OnReport sets a function called with the report of every crawl of Run, e.g. to alert on failed crawls.
+func (b *CollectorBuilder) OnReport(f func(r CrawlReport)) *CollectorBuilder {
+	b.r = f
 	return b
 }
 
@@ -60,61 +89,251 @@ func (b *CollectorBuilder) Build() (*Collector, error) {
 		b.l.Warnw("numWorker < 1, set to 1", "method", "Build")
 	}
 	if len(b.cc) == 0 {
-		b.cc = []Crawler{}
+		b.cc = []StreamCrawler{}
+	}
+
+	triggers := make(map[string]chan struct{}, len(b.cc))
+	busy := make(map[string]chan struct{}, len(b.cc))
+	for _, c := range b.cc {
+		if _, ok := triggers[c.Name()]; ok {
+			return nil, fmt.Errorf("crawler=%s provided twice", c.Name())
+		}
+		triggers[c.Name()] = make(chan struct{}, 1)
+		busy[c.Name()] = make(chan struct{}, 1)
+	}
+	for name, s := range b.ss {
+		if _, ok := triggers[name]; !ok {
+			return nil, fmt.Errorf("no crawler=%s provided for schedule", name)
+		}
+		if s.Timing == nil {
+			return nil, fmt.Errorf("no timing provided for schedule of crawler=%s", name)
+		}
 	}
 
 	return &Collector{
 		crawlers:  b.cc,
+		schedules: b.ss,
 		pub:       b.p,
 		log:       b.l,
 		numWorker: b.n,
+		triggers:  triggers,
+		busy:      busy,
+		onReport:  b.r,
 	}, nil
 }
 
-func (clr Collector) RunOnce() error {
-	clr.tasks = make(chan Crawler, clr.numWorker)
+// CrawlReport summarizes the crawl of a Crawler in a run.
+type CrawlReport struct {
+	Crawler string
+	// Found is the number of articles returned by the crawler.
+	Found     int
+	Published int
+	// Skipped is the number of articles found twice or already collected by a concurrent run.
+	Skipped  int
+	Errors   []error
+	Duration time.Duration
+}
+
+// Report summarizes a run of the Collector.
+type Report struct {
+	Run      string
+	Started  time.Time
+	Duration time.Duration
+	Crawlers []CrawlReport
+}
+
+// Failed returns the number of crawlers with errors.
+func (r Report) Failed() int {
+	n := 0
+	for _, c := range r.Crawlers {
+		if len(c.Errors) != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// RunOnce crawls all crawlers and publishes their articles. Failures of a crawler or of publishing an article
+// are isolated, they are recorded in the report of the crawler and the other crawlers continue.
+func (clr Collector) RunOnce() (Report, error) {
+	run, err := uuid.NewV4()
+	if err != nil {
+		return Report{}, fmt.Errorf("could not create run id, %w", err)
+	}
+
+	r := Report{Run: run.String(), Started: time.Now(), Crawlers: make([]CrawlReport, len(clr.crawlers))}
+	tasks := make(chan int, clr.numWorker)
 
 	clr.log.Infow("setup worker pool", "method", "RunOnce", "numWorker", clr.numWorker)
-	eg := new(errgroup.Group)
+	var wg sync.WaitGroup
 	for i := 0; i < clr.numWorker; i++ {
 		clr.log.Debugw("start collecting", "method", "RunOnce", "id", i)
-		eg.Go(clr.collect)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range tasks {
+				r.Crawlers[i] = clr.collect(clr.crawlers[i], r.Run)
+			}
+			clr.log.Debugw("finished collecting", "method", "RunOnce")
+		}()
 	}
 
 	clr.log.Infow(
 		"start collecting",
 		"method", "RunOnce",
 		"numCrawler", strconv.Itoa(len(clr.crawlers)),
+		"run", r.Run,
 	)
 
-	for i, c := range clr.crawlers {
+	for i := range clr.crawlers {
 		clr.log.Debugw("add task", "method", "RunOnce", "id", i)
-		clr.tasks <- c
+		tasks <- i
 	}
 
-	close(clr.tasks)
-	return eg.Wait()
+	close(tasks)
+	wg.Wait()
+
+	r.Duration = time.Since(r.Started)
+	return r, nil
 }
 
-func (clr Collector) collect() error {
-	for c := range clr.tasks {
-		clr.log.Debugw("crawling resource", "method", "collect", "resource", c.Name())
+// collect publishes the articles of c correlated by run while c crawls, a panicking crawler is recorded
+// as error. A crawler which is running already is not run again.
+func (clr Collector) collect(c StreamCrawler, run string) (cr CrawlReport) {
+	cr.Crawler = c.Name()
+	select {
+	case clr.busy[c.Name()] <- struct{}{}:
+		defer func() { <-clr.busy[c.Name()] }()
+	default:
+		clr.log.Infow("crawler running already, skip", "method", "collect", "resource", c.Name())
+		cr.Errors = append(cr.Errors, fmt.Errorf("crawler=%s is running already", c.Name()))
+		return cr
+	}
+
+	start := time.Now()
+	defer func() {
+		cr.Duration = time.Since(start)
+		clr.log.Infow(
+			"crawled resource",
+			"method", "collect",
+			"resource", cr.Crawler,
+			"found", cr.Found,
+			"published", cr.Published,
+			"skipped", cr.Skipped,
+			"errors", len(cr.Errors),
+			"duration", cr.Duration,
+		)
+	}()
+
+	clr.log.Debugw("crawling resource", "method", "collect", "resource", c.Name())
+	articles, errC := streamed(c)
+
+	seen := make(map[string]bool)
+	for a := range articles {
+		cr.Found++
+		// crawlers emit articles without id, the id the publisher would derive identifies duplicates
+		if len(a.ID) == 0 {
+			a.ID = ArticleID(a)
+		}
+		if seen[a.ID] {
+			cr.Skipped++
+			continue
+		}
+		seen[a.ID] = true
+
+		clr.log.Debugw("publish articles", "method", "collect", "title", a.Title)
+		a.Meta.CorrelationID = run
 
-		articles, err := c.Crawl()
+		err := clr.pub.Publish(a)
+		var wev *WrongExpectedVersionError
+		if errors.As(err, &wev) {
+			cr.Skipped++
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("could not crawl resource=%s, %w", c.Name(), err)
+			clr.log.Warnw("publish error", "method", "collect", "articleID", a.ID, "errMsg", err.Error())
+			cr.Errors = append(cr.Errors, fmt.Errorf("could not publish article with id=%s, %w", a.ID, err))
+			continue
 		}
+		cr.Published++
+	}
+
+	err := <-errC
+	if err != nil {
+		clr.log.Warnw("crawl error", "method", "collect", "resource", c.Name(), "errMsg", err.Error())
+		cr.Errors = append(cr.Errors, fmt.Errorf("could not crawl resource=%s, %w", c.Name(), err))
+	}
+	return cr
+}
+
+// Run crawls every crawler on its schedule until stop is closed, crawlers without a schedule only run
+// when triggered. Every crawler runs independently and never overlaps with itself.
+func (clr Collector) Run(stop <-chan struct{}) {
+	var wg sync.WaitGroup
+	for _, c := range clr.crawlers {
+		wg.Add(1)
+		go func(c StreamCrawler) {
+			defer wg.Done()
+			clr.schedule(c, stop)
+		}(c)
+	}
+	wg.Wait()
+}
 
-		for _, a := range articles {
-			clr.log.Debugw("publish articles", "method", "collect", "title", a.Title)
+// Trigger requests an immediate run of the crawler with name from Run. Triggers of a crawler which
+// is running or triggered already are merged into one run after it.
+func (clr Collector) Trigger(name string) error {
+	trigger, ok := clr.triggers[name]
+	if !ok {
+		return fmt.Errorf("unknown crawler=%s", name)
+	}
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (clr Collector) schedule(c StreamCrawler, stop <-chan struct{}) {
+	s, scheduled := clr.schedules[c.Name()]
+	// due is the time the next scheduled run is due without jitter, a triggered run keeps it
+	var due time.Time
+	for {
+		var timer *time.Timer
+		var next <-chan time.Time
+		if scheduled {
+			if now := time.Now(); !due.After(now) {
+				due = s.following(due, now)
+			}
+			if !due.IsZero() {
+				at := s.jitter(due)
+				clr.log.Infow("schedule crawl", "method", "schedule", "resource", c.Name(), "next", at)
+				timer = time.NewTimer(time.Until(at))
+				next = timer.C
+			}
+		}
 
-			err = clr.pub.Publish(a)
-			if err != nil {
-				return fmt.Errorf("could not publish article with id=%s, %w", a.ID, err)
+		select {
+		case <-stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-next:
+		case <-clr.triggers[c.Name()]:
+			if timer != nil {
+				timer.Stop()
 			}
 		}
 
+		run, err := uuid.NewV4()
+		if err != nil {
+			clr.log.Errorw("could not create run id", "method", "schedule", "resource", c.Name(), "errMsg", err)
+			continue
+		}
+		cr := clr.collect(c, run.String())
+		if clr.onReport != nil {
+			clr.onReport(cr)
+		}
 	}
-	clr.log.Debugw("finished collecting", "method", "collect")
-	return nil
 }
@@ -0,0 +1,93 @@
+package colly
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+// Config are the politeness settings shared by all colly based crawlers.
+type Config struct {
+	UserAgent string
+	// RespectRobots skips all pages disallowed for UserAgent by the robots.txt of their host.
+	RespectRobots bool
+	// AllowedDomains restricts visits to these hosts, the host of the start page of a crawler if empty.
+	AllowedDomains []string
+	// MaxDepth limits the link depth of visits, the start page has depth 1. 0 means unlimited.
+	MaxDepth int
+	// Delay is the min time between two requests to a domain, RandomDelay adds up to RandomDelay to it.
+	Delay       time.Duration
+	RandomDelay time.Duration
+	// Parallelism is the max number of concurrent requests to a domain.
+	Parallelism int
+	// Timeout of every request.
+	Timeout time.Duration
+	// Pages caches the ETag and Last-Modified of fetched pages to send conditional requests, nil disables them.
+	// The start page and robots.txt are always requested unconditionally, so their links and rules are read.
+	Pages newsReader.PageCache
+	// CacheBodies caches the bodies of pages too, so unchanged pages are parsed again from the cache.
+	// Otherwise unchanged pages are skipped.
+	CacheBodies bool
+}
+
+// DefaultConfig crawls start pages and the articles they link to, one second apart with two requests in parallel.
+func DefaultConfig() Config {
+	return Config{
+		UserAgent:     "newsReader-collector/1.0",
+		RespectRobots: true,
+		MaxDepth:      2,
+		Delay:         time.Second,
+		RandomDelay:   time.Second,
+		Parallelism:   2,
+		Timeout:       30 * time.Second,
+	}
+}
+
+// collector returns an async collector starting at start with the politeness settings of c applied.
+func (c Config) collector(start string, l *zap.SugaredLogger) (*colly.Collector, error) {
+	u, err := url.Parse(start)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse url=%s, %w", start, err)
+	}
+	domains := c.AllowedDomains
+	if len(domains) == 0 {
+		domains = []string{u.Hostname()}
+	}
+
+	opts := []colly.CollectorOption{colly.AllowedDomains(domains...), colly.MaxDepth(c.MaxDepth), colly.Async(true)}
+	if len(c.UserAgent) != 0 {
+		opts = append(opts, colly.UserAgent(c.UserAgent))
+	}
+	cltr := colly.NewCollector(opts...)
+	cltr.IgnoreRobotsTxt = !c.RespectRobots
+	if c.Pages != nil {
+		cltr.WithTransport(
+			conditional{next: http.DefaultTransport, pages: c.Pages, bodies: c.CacheBodies, entry: page(u), log: l},
+		)
+	}
+	if c.Timeout > 0 {
+		cltr.SetRequestTimeout(c.Timeout)
+	}
+
+	parallelism := c.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	err = cltr.Limit(
+		&colly.LimitRule{
+			DomainGlob:  "*",
+			Delay:       c.Delay,
+			RandomDelay: c.RandomDelay,
+			Parallelism: parallelism,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not set limit rule, %w", err)
+	}
+	return cltr, nil
+}
@@ -0,0 +1,176 @@
+package colly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// site serves a start page linking to articles, an article linking further, a page disallowed by robots.txt and
+// a link to a foreign domain. It records the visited paths and the max number of concurrent requests.
+type site struct {
+	mu        sync.Mutex
+	visited   []string
+	agents    map[string]bool
+	running   int
+	maxActive int
+}
+
+func (s *site) handler(delay time.Duration) http.Handler {
+	teaser := func(href string) string {
+		return fmt.Sprintf(`<a class="teaser__link" href="%s">link</a>`, href)
+	}
+	article := func(title, links string) string {
+		return fmt.Sprintf(
+			`<html><body><article class="container"><span class="seitenkopf__headline--text">%s</span>%s</article></body></html>`,
+			title, links,
+		)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		},
+	)
+	mux.HandleFunc(
+		"/", func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			s.visited = append(s.visited, r.URL.Path)
+			s.agents[r.UserAgent()] = true
+			s.running++
+			if s.running > s.maxActive {
+				s.maxActive = s.running
+			}
+			s.mu.Unlock()
+
+			time.Sleep(delay)
+
+			switch r.URL.Path {
+			case "/":
+				_, _ = fmt.Fprint(
+					w, "<html><body>"+teaser("/a")+teaser("/b")+teaser("/c")+teaser("/private")+
+						teaser("http://example.com/foreign")+"</body></html>",
+				)
+			case "/a":
+				_, _ = fmt.Fprint(w, article("a", teaser("/deep")))
+			default:
+				_, _ = fmt.Fprint(w, article(r.URL.Path[1:], ""))
+			}
+
+			s.mu.Lock()
+			s.running--
+			s.mu.Unlock()
+		},
+	)
+	return mux
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantVisited  []string
+		wantArticles []string
+	}{
+		{
+			name: "polite",
+			cfg: Config{
+				UserAgent:     "test-agent",
+				RespectRobots: true,
+				MaxDepth:      2,
+				Parallelism:   1,
+				Timeout:       time.Second,
+			},
+			wantVisited:  []string{"/", "/a", "/b", "/c"},
+			wantArticles: []string{"a", "b", "c"},
+		},
+		{
+			name: "ignore robots",
+			cfg: Config{
+				UserAgent:   "test-agent",
+				MaxDepth:    2,
+				Parallelism: 2,
+				Timeout:     time.Second,
+			},
+			wantVisited:  []string{"/", "/a", "/b", "/c", "/private"},
+			wantArticles: []string{"a", "b", "c", "private"},
+		},
+		{
+			name: "unlimited depth",
+			cfg: Config{
+				UserAgent:     "test-agent",
+				RespectRobots: true,
+				Parallelism:   2,
+				Timeout:       time.Second,
+			},
+			wantVisited:  []string{"/", "/a", "/b", "/c", "/deep"},
+			wantArticles: []string{"a", "b", "c", "deep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				s := &site{agents: make(map[string]bool)}
+				srv := httptest.NewServer(s.handler(20 * time.Millisecond))
+				defer srv.Close()
+
+				tsc := NewTagesschauCrawler(tt.cfg, zap.NewNop().Sugar())
+				tsc.url = srv.URL + "/"
+
+				got, err := tsc.Crawl()
+				if err != nil {
+					t.Fatalf("Crawl() error = %v", err)
+				}
+
+				titles := make([]string, 0, len(got))
+				for _, a := range got {
+					titles = append(titles, a.Title)
+				}
+				sort.Strings(titles)
+				if fmt.Sprint(titles) != fmt.Sprint(tt.wantArticles) {
+					t.Errorf("Crawl() got = %v, want %v", titles, tt.wantArticles)
+				}
+
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				sort.Strings(s.visited)
+				if fmt.Sprint(s.visited) != fmt.Sprint(tt.wantVisited) {
+					t.Errorf("visited = %v, want %v", s.visited, tt.wantVisited)
+				}
+				if len(s.agents) != 1 || !s.agents[tt.cfg.UserAgent] {
+					t.Errorf("user agents = %v, want %v", s.agents, tt.cfg.UserAgent)
+				}
+				if s.maxActive > tt.cfg.Parallelism {
+					t.Errorf("concurrent requests = %v, want at most %v", s.maxActive, tt.cfg.Parallelism)
+				}
+			},
+		)
+	}
+}
+
+func TestConfigTimeout(t *testing.T) {
+	s := &site{agents: make(map[string]bool)}
+	srv := httptest.NewServer(s.handler(200 * time.Millisecond))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.Delay, cfg.RandomDelay, cfg.Timeout = 0, 0, 50*time.Millisecond
+	tsc := NewTagesschauCrawler(cfg, zap.NewNop().Sugar())
+	tsc.url = srv.URL + "/"
+
+	got, err := tsc.Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Crawl() got %v articles of timed out requests, want 0", len(got))
+	}
+}
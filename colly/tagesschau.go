@@ -1,6 +1,9 @@
 package colly
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -13,29 +16,53 @@ import (
 )
 
 type Tagesschau struct {
+	url string
+	cfg Config
 	log *zap.SugaredLogger
 }
 
-func NewTagesschauCrawler(l *zap.SugaredLogger) *Tagesschau {
-	return &Tagesschau{log: l}
+func NewTagesschauCrawler(cfg Config, l *zap.SugaredLogger) *Tagesschau {
+	return &Tagesschau{url: "https://www.tagesschau.de", cfg: cfg, log: l}
 }
 
 func (t Tagesschau) Name() string {
 	return "tagesschau"
 }
 
+// Crawl returns all articles at the end of the crawl.
 func (t *Tagesschau) Crawl() ([]newsReader.Article, error) {
-	url := "https://www.tagesschau.de"
-	t.log.Infow("start crawling", "method", "Crawl", "url", url)
+	c := make(chan newsReader.Article)
+	errC := make(chan error, 1)
+	go func() {
+		defer close(c)
+		errC <- t.Stream(c)
+	}()
 
-	cltr := colly.NewCollector()
 	articles := make([]newsReader.Article, 0)
+	for a := range c {
+		articles = append(articles, a)
+	}
+	return articles, <-errC
+}
+
+// Stream sends every article on c as soon as its page is parsed.
+func (t *Tagesschau) Stream(c chan<- newsReader.Article) error {
+	t.log.Infow("start crawling", "method", "Stream", "url", t.url)
+
+	cltr, err := t.cfg.collector(t.url, t.log)
+	if err != nil {
+		return fmt.Errorf("could not create collector, %w", err)
+	}
+	var numArticles uint32
 
 	cltr.OnHTML(
 		"a.teaser__link", func(elem *colly.HTMLElement) {
 			link := elem.Attr("href")
 			err := elem.Request.Visit(link)
-			if err != nil {
+			switch {
+			case skipped(err):
+				t.log.Debugw("skip link", "method", "Stream", "url", link, "reason", err)
+			case err != nil:
 				t.log.Errorf("could not visit link=%v", link)
 			}
 		},
@@ -73,42 +100,80 @@ func (t *Tagesschau) Crawl() ([]newsReader.Article, error) {
 				Title:     titel,
 			}
 
-			articles = append(articles, a)
+			atomic.AddUint32(&numArticles, 1)
+			c <- a
 		},
 	)
 
-	var numVisited uint32
+	var numVisited, numUnchanged uint32
 	cltr.OnRequest(
 		func(r *colly.Request) {
 			r.Ctx.Put("url", r.URL.String())
-			r.Ctx.Put("date", time.Now().String())
-			t.log.Debugw("visiting website", "method", "Crawl", "url", r.URL)
+			r.Ctx.Put("date", time.Now().UTC().Format(time.RFC3339))
+			t.log.Debugw("visiting website", "method", "Stream", "url", r.URL)
 			atomic.AddUint32(&numVisited, 1)
 		},
 	)
 
-	err := cltr.Visit(url)
+	cltr.OnError(
+		func(r *colly.Response, err error) {
+			if r.StatusCode == http.StatusNotModified {
+				t.log.Debugw("website not modified", "method", "Stream", "url", r.Request.URL)
+				atomic.AddUint32(&numUnchanged, 1)
+				return
+			}
+			t.log.Warnw("could not fetch website", "method", "Stream", "url", r.Request.URL, "errMsg", err)
+		},
+	)
+
+	err = cltr.Visit(t.url)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	cltr.Wait()
 
 	t.log.Infow(
 		"finished crawling",
-		"method", "Crawl",
+		"method", "Stream",
 		"numVisited", numVisited,
-		"numArticles", strconv.Itoa(len(articles)),
+		"numUnchanged", numUnchanged,
+		"numArticles", strconv.Itoa(int(numArticles)),
 	)
-	return articles, nil
+	return nil
+}
+
+// skipped returns true if a link was not visited because of the politeness settings or because it was visited
+// already.
+func skipped(err error) bool {
+	for _, e := range []error{
+		colly.ErrAlreadyVisited, colly.ErrForbiddenDomain, colly.ErrMaxDepth, colly.ErrRobotsTxtBlocked,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
 }
 
 func (t Tagesschau) cleanBody(s string) string {
 	return strings.ReplaceAll(s, "\n", "")
 }
 
-// todo cast to time
+// cleanDate converts dates like "Stand: 14.01.2022 18:35 Uhr" to RFC3339, unknown formats are kept as is.
 func (t Tagesschau) cleanDate(s string) string {
 	s = strings.ReplaceAll(s, "\n", "")
 	s = strings.TrimLeft(s, " ")
 	s = strings.TrimRight(s, " ")
-	return s
+
+	d := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "Stand:"), "Uhr"))
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		loc = time.UTC
+	}
+	created, err := time.ParseInLocation("02.01.2006 15:04", d, loc)
+	if err != nil {
+		t.log.Debugw("could not parse date", "method", "cleanDate", "date", s)
+		return s
+	}
+	return created.Format(time.RFC3339)
 }
@@ -0,0 +1,43 @@
+package colly
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestCleanDate(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{
+			name: "pass",
+			arg:  "\n  Stand: 14.01.2022 18:35 Uhr \n",
+			want: "2022-01-14T18:35:00+01:00",
+		},
+		{
+			name: "summer time",
+			arg:  "Stand: 14.07.2022 18:35 Uhr",
+			want: "2022-07-14T18:35:00+02:00",
+		},
+		{
+			name: "unknown format",
+			arg:  " gestern ",
+			want: "gestern",
+		},
+	}
+
+	tsc := NewTagesschauCrawler(DefaultConfig(), zap.NewNop().Sugar())
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := tsc.cleanDate(tt.arg)
+				if got != tt.want {
+					t.Errorf("cleanDate() got = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,155 @@
+package colly
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+// conditional is a http.RoundTripper sending conditional GET requests with the validators of the cached
+// pages, so unchanged pages cost a 304. If the body of a page is cached, a 304 is answered with it as 200,
+// otherwise the 304 is returned. The entry page and robots.txt are requested unconditionally, as a 304
+// without body would end the crawl or fail the robots check.
+type conditional struct {
+	next   http.RoundTripper
+	pages  newsReader.PageCache
+	bodies bool
+	entry  string
+	log    *zap.SugaredLogger
+}
+
+func (c conditional) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.URL.Path == "/robots.txt" || page(req.URL) == c.entry {
+		return c.next.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	page, err := c.pages.Load(url)
+	cached := err == nil
+	if err != nil && !errors.Is(err, newsReader.ErrNotFound) {
+		c.log.Warnw("could not load page", "method", "RoundTrip", "url", url, "errMsg", err)
+	}
+
+	if cached {
+		req = req.Clone(req.Context())
+		if len(page.ETag) != 0 {
+			req.Header.Set("If-None-Match", page.ETag)
+		}
+		if len(page.LastModified) != 0 {
+			req.Header.Set("If-Modified-Since", page.LastModified)
+		}
+	}
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached && len(page.Body) != 0:
+		c.log.Debugw("page not modified, use cached body", "method", "RoundTrip", "url", url)
+		return c.revalidated(resp, page), nil
+	case resp.StatusCode == http.StatusOK:
+		return c.save(url, resp, cached), nil
+	}
+	return resp, nil
+}
+
+// revalidated returns the cached page as response to the request of the 304 resp.
+func (c conditional) revalidated(resp *http.Response, page newsReader.Page) *http.Response {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	h := resp.Header.Clone()
+	h.Del("Content-Encoding")
+	h.Set("Content-Type", page.ContentType)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         resp.Proto,
+		ProtoMajor:    resp.ProtoMajor,
+		ProtoMinor:    resp.ProtoMinor,
+		Header:        h,
+		Body:          ioutil.NopCloser(bytes.NewReader(page.Body)),
+		ContentLength: int64(len(page.Body)),
+		Request:       resp.Request,
+		Uncompressed:  true,
+	}
+}
+
+// save caches the validators and, if bodies are cached, the body of resp once the body has been read
+// completely, so pages whose body failed to be read are requested unconditionally again. Pages without
+// validators are only saved to replace a cached version.
+func (c conditional) save(url string, resp *http.Response, cached bool) *http.Response {
+	page := newsReader.Page{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+	}
+	if len(page.ETag) == 0 && len(page.LastModified) == 0 {
+		if cached {
+			c.put(url, newsReader.Page{})
+		}
+		return resp
+	}
+
+	if c.bodies {
+		b, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			// the caller gets the error when reading the body
+			resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(b), errReader{err}))
+			return resp
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		page.Body = b
+		c.put(url, page)
+		return resp
+	}
+
+	resp.Body = &onEOF{ReadCloser: resp.Body, f: func() { c.put(url, page) }}
+	return resp
+}
+
+func (c conditional) put(url string, page newsReader.Page) {
+	err := c.pages.Save(url, page)
+	if err != nil {
+		c.log.Warnw("could not save page", "method", "RoundTrip", "url", url, "errMsg", err)
+	}
+}
+
+// page identifies the page of u regardless of a trailing slash of its path.
+func page(u *url.URL) string {
+	return u.Host + strings.TrimSuffix(u.Path, "/") + "?" + u.RawQuery
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// onEOF calls f once the wrapped body has been read up to io.EOF.
+type onEOF struct {
+	io.ReadCloser
+	f    func()
+	done bool
+}
+
+func (r *onEOF) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF && !r.done {
+		r.done = true
+		r.f()
+	}
+	return n, err
+}
@@ -0,0 +1,226 @@
+package colly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader/memory"
+)
+
+// versioned serves a robots.txt and a start page linking to two articles, every page with an ETag and a
+// Last-Modified header. It answers matching conditional requests with 304 and records the status of every response.
+// The next response of a truncated page breaks off in the body.
+type versioned struct {
+	mu        sync.Mutex
+	versions  map[string]int
+	statuses  map[int]int
+	truncated map[string]bool
+}
+
+func (v *versioned) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	version := v.versions[r.URL.Path]
+	v.mu.Unlock()
+
+	etag := fmt.Sprintf(`"%s-%d"`, r.URL.Path, version)
+	modified := time.Date(2026, 10, 19, 8, version, 0, 0, time.UTC).Format(http.TimeFormat)
+	status := http.StatusOK
+	if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == modified {
+		status = http.StatusNotModified
+	}
+
+	v.mu.Lock()
+	v.statuses[status]++
+	truncated := v.truncated[r.URL.Path]
+	delete(v.truncated, r.URL.Path)
+	v.mu.Unlock()
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", modified)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if truncated && status == http.StatusOK {
+		w.Header().Set("Content-Length", "1000")
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, "<html>")
+		return
+	}
+	w.WriteHeader(status)
+	if status != http.StatusOK {
+		return
+	}
+
+	if r.URL.Path == "/robots.txt" {
+		_, _ = fmt.Fprint(w, "User-agent: *\nAllow: /\n")
+		return
+	}
+	if r.URL.Path == "/" {
+		_, _ = fmt.Fprint(
+			w, `<html><body><a class="teaser__link" href="/a">a</a><a class="teaser__link" href="/b">b</a></body></html>`,
+		)
+		return
+	}
+	_, _ = fmt.Fprintf(
+		w, `<html><body><article class="container"><span class="seitenkopf__headline--text">%s-%d</span></article></body></html>`,
+		r.URL.Path[1:], version,
+	)
+}
+
+func (v *versioned) reset() map[int]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s := v.statuses
+	v.statuses = make(map[int]int)
+	return s
+}
+
+func TestConditionalRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		cacheBodies bool
+		// pages changed before the second crawl
+		changed []string
+		// articles of the second crawl
+		want []string
+		// statuses of the second crawl
+		wantStatuses map[int]int
+	}{
+		{
+			name:         "validators only",
+			changed:      []string{"/", "/b"},
+			want:         []string{"b-1"},
+			wantStatuses: map[int]int{http.StatusOK: 2, http.StatusNotModified: 1},
+		},
+		{
+			name:         "validators only unchanged",
+			want:         []string{},
+			wantStatuses: map[int]int{http.StatusOK: 1, http.StatusNotModified: 2},
+		},
+		{
+			name:         "cached bodies",
+			cacheBodies:  true,
+			changed:      []string{"/", "/b"},
+			want:         []string{"a-0", "b-1"},
+			wantStatuses: map[int]int{http.StatusOK: 2, http.StatusNotModified: 1},
+		},
+		{
+			name:         "cached bodies unchanged",
+			cacheBodies:  true,
+			want:         []string{"a-0", "b-0"},
+			wantStatuses: map[int]int{http.StatusOK: 1, http.StatusNotModified: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				v := &versioned{versions: make(map[string]int), statuses: make(map[int]int), truncated: make(map[string]bool)}
+				srv := httptest.NewServer(v)
+				defer srv.Close()
+
+				cfg := Config{MaxDepth: 2, Parallelism: 1, Pages: memory.NewPageCache(10), CacheBodies: tt.cacheBodies}
+				tsc := NewTagesschauCrawler(cfg, zap.NewNop().Sugar())
+				tsc.url = srv.URL + "/"
+
+				first, err := tsc.Crawl()
+				if err != nil || len(first) != 2 {
+					t.Fatalf("want 2 articles of the first crawl, got %v, %v", len(first), err)
+				}
+				v.reset()
+
+				v.mu.Lock()
+				for _, p := range tt.changed {
+					v.versions[p]++
+				}
+				v.mu.Unlock()
+
+				second, err := tsc.Crawl()
+				if err != nil {
+					t.Fatalf("Crawl() error = %v", err)
+				}
+				titles := make([]string, 0, len(second))
+				for _, a := range second {
+					titles = append(titles, a.Title)
+				}
+				sort.Strings(titles)
+				if fmt.Sprint(titles) != fmt.Sprint(tt.want) {
+					t.Errorf("Crawl() got = %v, want %v", titles, tt.want)
+				}
+				if got := v.reset(); fmt.Sprint(got) != fmt.Sprint(tt.wantStatuses) {
+					t.Errorf("statuses = %v, want %v", got, tt.wantStatuses)
+				}
+			},
+		)
+	}
+}
+
+func TestConditionalRequestsBodyError(t *testing.T) {
+	v := &versioned{
+		versions:  make(map[string]int),
+		statuses:  make(map[int]int),
+		truncated: map[string]bool{"/b": true},
+	}
+	srv := httptest.NewServer(v)
+	defer srv.Close()
+
+	cfg := Config{MaxDepth: 2, Parallelism: 1, Pages: memory.NewPageCache(10)}
+	tsc := NewTagesschauCrawler(cfg, zap.NewNop().Sugar())
+	tsc.url = srv.URL + "/"
+
+	first, err := tsc.Crawl()
+	if err != nil || len(first) != 1 {
+		t.Fatalf("want 1 article of the first crawl, got %v, %v", len(first), err)
+	}
+
+	// the validators of the truncated page are not cached, so it is collected by the next crawl
+	v.mu.Lock()
+	v.versions["/"]++
+	v.mu.Unlock()
+
+	second, err := tsc.Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(second) != 1 || second[0].Title != "b-0" {
+		t.Errorf("Crawl() got = %v, want article b-0", second)
+	}
+}
+
+func TestConditionalRequestsRobots(t *testing.T) {
+	v := &versioned{versions: make(map[string]int), statuses: make(map[int]int), truncated: make(map[string]bool)}
+	srv := httptest.NewServer(v)
+	defer srv.Close()
+
+	cfg := Config{RespectRobots: true, MaxDepth: 2, Parallelism: 1, Pages: memory.NewPageCache(10)}
+	tsc := NewTagesschauCrawler(cfg, zap.NewNop().Sugar())
+	tsc.url = srv.URL + "/"
+
+	first, err := tsc.Crawl()
+	if err != nil || len(first) != 2 {
+		t.Fatalf("want 2 articles of the first crawl, got %v, %v", len(first), err)
+	}
+	v.reset()
+
+	// robots.txt and the unchanged start page are requested again, only the articles conditionally
+	v.mu.Lock()
+	v.versions["/b"]++
+	v.mu.Unlock()
+
+	second, err := tsc.Crawl()
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+	if len(second) != 1 || second[0].Title != "b-1" {
+		t.Errorf("Crawl() got = %v, want article b-1", second)
+	}
+	want := map[int]int{http.StatusOK: 3, http.StatusNotModified: 1}
+	if got := v.reset(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+}
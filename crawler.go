@@ -1,6 +1,54 @@
 package newsReader
 
+import "fmt"
+
 type Crawler interface {
 	Name() string
 	Crawl() ([]Article, error)
 }
+
+// StreamCrawler is a crawler sending every article on c as soon as it is parsed, so it can be published
+// while the crawl continues. Stream returns when the crawl is done, c is closed by the caller.
+type StreamCrawler interface {
+	Name() string
+	Stream(c chan<- Article) error
+}
+
+// Stream returns c as StreamCrawler. Crawlers which do not stream send all articles at the end of their crawl.
+func Stream(c Crawler) StreamCrawler {
+	if s, ok := c.(StreamCrawler); ok {
+		return s
+	}
+	return sliceCrawler{c}
+}
+
+type sliceCrawler struct {
+	Crawler
+}
+
+// Stream sends the articles crawled before an error, too.
+func (s sliceCrawler) Stream(c chan<- Article) error {
+	articles, err := s.Crawl()
+	for _, a := range articles {
+		c <- a
+	}
+	return err
+}
+
+// streamed runs the crawl of s and returns the channel of its articles and of its error, which is sent
+// after all articles. A panic of s is returned as error. The articles are closed when Stream returns or
+// panics, so a crawler never blocks its consumer.
+func streamed(s StreamCrawler) (<-chan Article, <-chan error) {
+	articles := make(chan Article)
+	errC := make(chan error, 1)
+	go func() {
+		defer close(articles)
+		defer func() {
+			if p := recover(); p != nil {
+				errC <- fmt.Errorf("crawler=%s panicked, %v", s.Name(), p)
+			}
+		}()
+		errC <- s.Stream(articles)
+	}()
+	return articles, errC
+}
@@ -0,0 +1,65 @@
+package disk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"newsReader"
+)
+
+// ResultCache is a newsReader.ResultCache storing every result as json file in a directory, so results
+// survive restarts and can be shared by the preprocessor and cmd/reprocess.
+type ResultCache struct {
+	dir string
+}
+
+// NewResultCache returns a ResultCache in dir, dir is created if missing.
+func NewResultCache(dir string) (*ResultCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cache dir=%s, %w", dir, err)
+	}
+	return &ResultCache{dir: dir}, nil
+}
+
+// path shards the files by the first two characters of the key.
+func (c ResultCache) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(c.dir, key+".json")
+	}
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c ResultCache) Load(key string) (newsReader.Article, error) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return newsReader.Article{}, newsReader.ErrNotFound
+	}
+	if err != nil {
+		return newsReader.Article{}, fmt.Errorf("could not read result=%s, %w", key, err)
+	}
+
+	var a newsReader.Article
+	err = json.Unmarshal(b, &a)
+	if err != nil {
+		return newsReader.Article{}, fmt.Errorf("could not unmarshal result=%s, %w", key, err)
+	}
+	return a, nil
+}
+
+func (c ResultCache) Save(key string, a newsReader.Article) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("could not marshal result=%s, %w", key, err)
+	}
+
+	err = write(c.path(key), b)
+	if err != nil {
+		return fmt.Errorf("could not write result=%s, %w", key, err)
+	}
+	return nil
+}
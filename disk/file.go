@@ -0,0 +1,35 @@
+package disk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// write writes b to a temporary file next to p first and renames it to p, so concurrent reads never read
+// partial files.
+func write(p string, b []byte) error {
+	err := os.MkdirAll(filepath.Dir(p), 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p), filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), p)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package disk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"newsReader"
+)
+
+// PageCache is a newsReader.PageCache storing every page as json file in a directory, so crawlers keep
+// sending conditional requests after restarts.
+type PageCache struct {
+	dir string
+}
+
+// NewPageCache returns a PageCache in dir, dir is created if missing.
+func NewPageCache(dir string) (*PageCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cache dir=%s, %w", dir, err)
+	}
+	return &PageCache{dir: dir}, nil
+}
+
+// path names the files by the sha256 of the url sharded by its first two characters.
+func (c PageCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c PageCache) Load(url string) (newsReader.Page, error) {
+	b, err := ioutil.ReadFile(c.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return newsReader.Page{}, newsReader.ErrNotFound
+	}
+	if err != nil {
+		return newsReader.Page{}, fmt.Errorf("could not read page=%s, %w", url, err)
+	}
+
+	var p newsReader.Page
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		return newsReader.Page{}, fmt.Errorf("could not unmarshal page=%s, %w", url, err)
+	}
+	return p, nil
+}
+
+func (c PageCache) Save(url string, p newsReader.Page) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not marshal page=%s, %w", url, err)
+	}
+
+	err = write(c.path(url), b)
+	if err != nil {
+		return fmt.Errorf("could not write page=%s, %w", url, err)
+	}
+	return nil
+}
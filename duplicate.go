@@ -0,0 +1,162 @@
+package newsReader
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// shingleSize is the number of words hashed together into a feature of the SimHash.
+const shingleSize = 3
+
+// Fingerprint is the SimHash of an article body.
+type Fingerprint struct {
+	ArticleID string
+	Hash      uint64
+	Time      time.Time
+	// Original is the ID of the earliest article the article is a near-duplicate of, its own ID otherwise.
+	Original string
+}
+
+// FingerprintStore looks up fingerprints by their Hamming distance.
+type FingerprintStore interface {
+	// Nearest returns the fingerprint closest to hash within maxDistance bits, ignoring the fingerprint of articleID.
+	Nearest(hash uint64, maxDistance int, articleID string) (Fingerprint, bool, error)
+	// Save replaces the fingerprint of an article but keeps the earliest Time saved for it.
+	Save(f Fingerprint) error
+}
+
+// Deduplicator is a Processor flagging near-duplicates, e.g. agency articles republished almost verbatim
+// by several outlets. Articles whose body SimHash differs in at most maxDistance bits from a fingerprint
+// in the store point to the earliest original via DuplicateOf.
+type Deduplicator struct {
+	store       FingerprintStore
+	maxDistance int
+	log         *zap.SugaredLogger
+}
+
+func NewDeduplicator(store FingerprintStore, maxDistance int, l *zap.SugaredLogger) *Deduplicator {
+	return &Deduplicator{store: store, maxDistance: maxDistance, log: l}
+}
+
+func (d Deduplicator) Name() string {
+	return "Duplicate"
+}
+
+func (d Deduplicator) Version() string {
+	return "simhash-1"
+}
+
+func (d Deduplicator) Reads() []Field {
+	return []Field{FieldBody, FieldCollected}
+}
+
+func (d Deduplicator) Writes() []Field {
+	return []Field{FieldDuplicateOf, FieldSimilarity}
+}
+
+// Process flags a as near-duplicate if an earlier article with a similar body is known. An article
+// processed before its original is kept as original.
+func (d Deduplicator) Process(a Article) (Article, error) {
+	a.DuplicateOf = ""
+	a.Similarity = 0
+
+	hash, ok := SimHash(a.Body)
+	if !ok {
+		return a, nil
+	}
+
+	f := Fingerprint{ArticleID: a.ID, Hash: hash, Time: articleTime(a), Original: a.ID}
+	near, found, err := d.store.Nearest(hash, d.maxDistance, a.ID)
+	if err != nil {
+		return a, fmt.Errorf("could not look up fingerprint of article=%s, %w", a.ID, err)
+	}
+	// a copy of a points back to a, e.g. if the original is recollected after its copies
+	if found && near.Original != a.ID && !near.Time.After(f.Time) {
+		f.Original = near.Original
+		a.DuplicateOf = near.Original
+		a.Similarity = Similarity(hash, near.Hash)
+		d.log.Debugw(
+			"near-duplicate",
+			"method", "Process",
+			"articleID", a.ID,
+			"original", a.DuplicateOf,
+			"similarity", a.Similarity,
+		)
+	}
+
+	err = d.store.Save(f)
+	if err != nil {
+		return a, fmt.Errorf("could not save fingerprint of article=%s, %w", a.ID, err)
+	}
+	return a, nil
+}
+
+// Warm saves the fingerprints of all articles of src, e.g. of all preprocessed articles after a restart
+// of a deduplicator with an in-memory store.
+func (d Deduplicator) Warm(src Source) error {
+	return Warm(src, d.log, d)
+}
+
+// Restore saves the fingerprint of a, pointing to the original a has been flagged as duplicate of.
+func (d Deduplicator) Restore(a Article) error {
+	hash, ok := SimHash(a.Body)
+	if !ok {
+		return nil
+	}
+	original := a.DuplicateOf
+	if len(original) == 0 {
+		original = a.ID
+	}
+	err := d.store.Save(Fingerprint{ArticleID: a.ID, Hash: hash, Time: articleTime(a), Original: original})
+	if err != nil {
+		return fmt.Errorf("could not save fingerprint of article=%s, %w", a.ID, err)
+	}
+	return nil
+}
+
+// SimHash returns the 64 bit SimHash of the word shingles of s, false if s has no words.
+func SimHash(s string) (uint64, bool) {
+	ww := words(s)
+	if len(ww) == 0 {
+		return 0, false
+	}
+
+	var weights [64]int
+	add := func(shingle string) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(shingle))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	if len(ww) < shingleSize {
+		add(strings.Join(ww, " "))
+	}
+	for i := 0; i+shingleSize <= len(ww); i++ {
+		add(strings.Join(ww[i:i+shingleSize], " "))
+	}
+
+	var hash uint64
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash, true
+}
+
+// Similarity returns the share of equal bits of the fingerprints a and b.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
@@ -0,0 +1,6 @@
+package newsReader
+
+import "errors"
+
+// ErrNotFound is returned by stores and caches for unknown keys.
+var ErrNotFound = errors.New("not found")
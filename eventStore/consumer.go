@@ -1,23 +1,95 @@
 package eventStore
 
 import (
+	"sync"
+
 	"go.uber.org/zap"
 	"newsReader"
 )
 
+// untilConsumer is a newsReader.Queue which can consume until stop is closed, e.g. Queue.
+type untilConsumer interface {
+	ConsumeUntil(eType string, c chan<- newsReader.Article, stop <-chan struct{})
+}
+
+// resumingQueue is a newsReader.Queue which can consume from where a replay ended, e.g. Queue.
+type resumingQueue interface {
+	ReplayPosition(eType string, c chan<- newsReader.Article) (uint64, error)
+	ConsumeFrom(eType string, position uint64, c chan<- newsReader.Article, stop <-chan struct{})
+}
+
 type Consumer struct {
-	queue newsReader.Queue
-	eType string
-	log   *zap.SugaredLogger
+	queue    newsReader.Queue
+	eType    string
+	stop     chan struct{}
+	once     *sync.Once
+	replayed *position
+	log      *zap.SugaredLogger
+}
+
+// position is the position after the last replay of a Consumer.
+type position struct {
+	mu    sync.Mutex
+	value uint64
+	ok    bool
 }
 
 func NewConsumer(q newsReader.Queue, eType string, l *zap.SugaredLogger) *Consumer {
 	return &Consumer{
-		queue: q,
-		eType: eType,
-		log:   l,
+		queue:    q,
+		eType:    eType,
+		stop:     make(chan struct{}),
+		once:     &sync.Once{},
+		replayed: &position{},
+		log:      l,
 	}
 }
+
+// Consume consumes new events, or the events appended since the last Replay if the queue supports it.
 func (c Consumer) Consume(a chan<- newsReader.Article) {
+	c.replayed.mu.Lock()
+	from, replayed := c.replayed.value, c.replayed.ok
+	c.replayed.mu.Unlock()
+
+	if q, ok := c.queue.(resumingQueue); ok && replayed {
+		c.log.Infow("consume from end of replay", "method", "Consume", "eventType", c.eType, "position", from)
+		q.ConsumeFrom(c.eType, from, a, c.stop)
+		return
+	}
+	if q, ok := c.queue.(untilConsumer); ok {
+		q.ConsumeUntil(c.eType, a, c.stop)
+		return
+	}
 	c.queue.Consume(c.eType, a)
 }
+
+// Stop ends the subscription of Consume if the queue supports it.
+func (c Consumer) Stop() {
+	c.once.Do(func() { close(c.stop) })
+}
+
+func (c Consumer) Replay(a chan<- newsReader.Article) error {
+	q, ok := c.queue.(resumingQueue)
+	if !ok {
+		return c.queue.Replay(c.eType, a)
+	}
+
+	from, err := q.ReplayPosition(c.eType, a)
+	if err != nil {
+		return err
+	}
+	c.replayed.mu.Lock()
+	c.replayed.value, c.replayed.ok = from, true
+	c.replayed.mu.Unlock()
+	return nil
+}
+
+func (c Consumer) Latest(id string) (newsReader.Article, bool, error) {
+	a, eType, err := c.queue.Latest(id)
+	if err != nil {
+		return newsReader.Article{}, false, err
+	}
+
+	c.log.Debugw("read latest article", "method", "Latest", "articleID", id, "eventType", eType)
+	return a, eType == c.eType, nil
+}
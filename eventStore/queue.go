@@ -3,22 +3,54 @@ package eventStore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 	"newsReader"
 )
 
+// Producer identifies the service appending events to the queue.
+type Producer struct {
+	Service string
+	Version string
+	Host    string
+}
+
+// events are the events of a read stream, e.g. an esdb.ReadStream.
+type events interface {
+	Recv() (*esdb.ResolvedEvent, error)
+	Close()
+}
+
+// readStream opens a read of up to count events of streamID, e.g. esdb.Client.ReadStream.
+type readStream func(ctx context.Context, streamID string, opts esdb.ReadStreamOptions, count uint64) (events, error)
+
+func clientRead(db *esdb.Client) readStream {
+	return func(ctx context.Context, streamID string, opts esdb.ReadStreamOptions, count uint64) (events, error) {
+		stream, err := db.ReadStream(ctx, streamID, opts, count)
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	}
+}
+
 type Queue struct {
 	db        *esdb.Client
+	read      readStream
 	log       *zap.SugaredLogger
+	producer  Producer
 	timeout   time.Duration
 	batchSize uint64
 }
 
-func NewQueue(user, pwd, addr string, log *zap.SugaredLogger) (*Queue, error) {
+func NewQueue(user, pwd, addr string, p Producer, log *zap.SugaredLogger) (*Queue, error) {
 	conn, err := esdb.ParseConnectionString(fmt.Sprintf("esdb://%s:%s@%s?tls=false", user, pwd, addr))
 	if err != nil {
 		return nil, fmt.Errorf("could not parse connection string, %w", err)
@@ -29,9 +61,18 @@ func NewQueue(user, pwd, addr string, log *zap.SugaredLogger) (*Queue, error) {
 		return nil, fmt.Errorf("could not create new client, %w", err)
 	}
 
+	if len(p.Host) == 0 {
+		p.Host, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("could not read hostname, %w", err)
+		}
+	}
+
 	return &Queue{
 		db:        db,
+		read:      clientRead(db),
 		log:       log,
+		producer:  p,
 		batchSize: 30,
 		timeout:   time.Second * 10,
 	}, nil
@@ -44,15 +85,42 @@ func (q Queue) Publish(a newsReader.Article, eType string) error {
 		return fmt.Errorf("could not marshal articleID=%v, %w", a.ID, err)
 	}
 
+	id, err := uuid.NewV4()
+	if err != nil {
+		return fmt.Errorf("could not create eventID for articleID=%v, %w", a.ID, err)
+	}
+
+	meta, err := json.Marshal(q.metadata(a.Meta, id.String()))
+	if err != nil {
+		return fmt.Errorf("could not marshal metadata of articleID=%v, %w", a.ID, err)
+	}
+
 	event := esdb.EventData{
+		EventID:     id,
 		ContentType: esdb.JsonContentType,
 		EventType:   eType,
 		Data:        bytes,
+		Metadata:    meta,
+	}
+
+	opts := esdb.AppendToStreamOptions{}
+	if a.Version > 0 {
+		opts.ExpectedRevision = esdb.Revision(a.Version - 1)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
 	defer cancel()
-	_, err = q.db.AppendToStream(ctx, a.ID, esdb.AppendToStreamOptions{}, event)
+	_, err = q.db.AppendToStream(ctx, a.ID, opts, event)
+	if errors.Is(err, esdb.ErrWrongExpectedStreamRevision) {
+		q.log.Infow(
+			"wrong expected version",
+			"method", "Publish",
+			"articleID", a.ID,
+			"eventType", eType,
+			"version", a.Version,
+		)
+		return &newsReader.WrongExpectedVersionError{StreamID: a.ID, Expected: a.Version}
+	}
 	if err != nil {
 		return fmt.Errorf("could not append eventType=%v to streamID=%v, %w", eType, a.ID, err)
 	}
@@ -60,11 +128,111 @@ func (q Queue) Publish(a newsReader.Article, eType string) error {
 	return nil
 }
 
+// metadata derives the metadata of the event with id, caused by the event with metadata cause.
+func (q Queue) metadata(cause newsReader.Metadata, id string) newsReader.Metadata {
+	m := cause
+	m.EventID = id
+	m.CausationID = cause.EventID
+	if len(m.CausationID) == 0 {
+		m.CausationID = id
+	}
+	if len(m.CorrelationID) == 0 {
+		m.CorrelationID = m.CausationID
+	}
+	m.Producer = q.producer.Service
+	m.ProducerVersion = q.producer.Version
+	m.Host = q.producer.Host
+
+	return m
+}
+
+// PublishStory appends the story to its own stream, caused by the article event which changed it.
+func (q Queue) PublishStory(s newsReader.Story, eType string) error {
+	q.log.Debugw("publish story", "method", "PublishStory", "storyID", s.ID, "eventType", eType)
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("could not marshal storyID=%v, %w", s.ID, err)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return fmt.Errorf("could not create eventID for storyID=%v, %w", s.ID, err)
+	}
+
+	m := q.metadata(s.Meta, id.String())
+	m.Processors = nil
+	m.Inputs = nil
+	meta, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("could not marshal metadata of storyID=%v, %w", s.ID, err)
+	}
+
+	event := esdb.EventData{
+		EventID:     id,
+		ContentType: esdb.JsonContentType,
+		EventType:   eType,
+		Data:        bytes,
+		Metadata:    meta,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+	_, err = q.db.AppendToStream(ctx, s.ID, esdb.AppendToStreamOptions{}, event)
+	if err != nil {
+		return fmt.Errorf("could not append eventType=%v to streamID=%v, %w", eType, s.ID, err)
+	}
+	return nil
+}
+
+func (q Queue) Latest(id string) (newsReader.Article, string, error) {
+	q.log.Debugw("read latest", "method", "Latest", "streamID", id)
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+	stream, err := q.db.ReadStream(ctx, id, esdb.ReadStreamOptions{Direction: esdb.Backwards, From: esdb.End{}}, 1)
+	if err != nil {
+		return newsReader.Article{}, "", fmt.Errorf("could not read streamID=%v, %w", id, err)
+	}
+	defer stream.Close()
+
+	evt, err := stream.Recv()
+	if err != nil {
+		return newsReader.Article{}, "", fmt.Errorf("could not receive latest event of streamID=%v, %w", id, err)
+	}
+
+	a, err := article(evt.Event)
+	if err != nil {
+		return newsReader.Article{}, "", err
+	}
+
+	return a, evt.Event.EventType, nil
+}
+
 func (q Queue) Consume(eType string, c chan<- newsReader.Article) {
+	q.ConsumeUntil(eType, c, nil)
+}
+
+// ConsumeUntil consumes new events of eType until the subscription drops or stop is closed and closes c.
+func (q Queue) ConsumeUntil(eType string, c chan<- newsReader.Article, stop <-chan struct{}) {
+	q.subscribe(eType, esdb.End{}, c, stop)
+}
+
+// ConsumeFrom consumes the events of eType from position on, e.g. the position ReplayPosition returned,
+// until the subscription drops or stop is closed and closes c.
+func (q Queue) ConsumeFrom(eType string, position uint64, c chan<- newsReader.Article, stop <-chan struct{}) {
+	var from esdb.StreamPosition = esdb.Start{}
+	if position > 0 {
+		// subscriptions start after the given revision
+		from = esdb.Revision(position - 1)
+	}
+	q.subscribe(eType, from, c, stop)
+}
+
+func (q Queue) subscribe(eType string, from esdb.StreamPosition, c chan<- newsReader.Article, stop <-chan struct{}) {
 	q.log.Debugw("consume", "method", "Consume", "eventType", eType)
 
 	stream, err := q.db.SubscribeToStream(
-		context.Background(), fmt.Sprintf("$et-%s", eType), esdb.SubscribeToStreamOptions{ResolveLinkTos: true},
+		context.Background(), fmt.Sprintf("$et-%s", eType), esdb.SubscribeToStreamOptions{From: from, ResolveLinkTos: true},
 	)
 	if err != nil {
 		q.log.Errorw("could not subscribe to stream", "method", "Consume", "errMsg", err)
@@ -78,18 +246,111 @@ func (q Queue) Consume(eType string, c chan<- newsReader.Article) {
 		}
 	}(stream)
 
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-stop:
+			q.log.Infow("stop subscription", "method", "Consume", "eventType", eType)
+			_ = stream.Close()
+		case <-stopped:
+		}
+	}()
+
 	q.loopStream(stream, c)
 }
 
+// Replay reads all past events of eType from the start of the event type stream
+// in batches of batchSize and closes c when done.
+func (q Queue) Replay(eType string, c chan<- newsReader.Article) error {
+	_, err := q.ReplayPosition(eType, c)
+	return err
+}
+
+// ReplayPosition replays like Replay and returns the position after the last replayed event, so ConsumeFrom
+// continues with the events appended since.
+func (q Queue) ReplayPosition(eType string, c chan<- newsReader.Article) (uint64, error) {
+	q.log.Debugw("replay", "method", "Replay", "eventType", eType)
+	defer close(c)
+
+	var from uint64
+	for {
+		aa, n, next, err := q.readBatch(fmt.Sprintf("$et-%s", eType), from)
+		if err != nil {
+			return from, err
+		}
+
+		for _, a := range aa {
+			c <- a
+		}
+
+		from = next
+		if n < q.batchSize {
+			return from, nil
+		}
+	}
+}
+
+// readBatch reads up to batchSize events of streamID starting at revision from. It returns
+// the articles, the number of events read and the revision to continue from.
+func (q Queue) readBatch(streamID string, from uint64) ([]newsReader.Article, uint64, uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+
+	opts := esdb.ReadStreamOptions{Direction: esdb.Forwards, From: esdb.Revision(from), ResolveLinkTos: true}
+	stream, err := q.read(ctx, streamID, opts, q.batchSize)
+	// reading past the end of an existing stream returns io.EOF, e.g. if its length is a multiple of batchSize
+	if errors.Is(err, esdb.ErrStreamNotFound) || errors.Is(err, io.EOF) {
+		return nil, 0, from, nil
+	}
+	if err != nil {
+		return nil, 0, from, fmt.Errorf("could not read streamID=%v from=%v, %w", streamID, from, err)
+	}
+	defer stream.Close()
+
+	var n uint64
+	aa := make([]newsReader.Article, 0, q.batchSize)
+	for {
+		evt, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return aa, n, from, nil
+		}
+		if err != nil {
+			return nil, n, from, fmt.Errorf("could not receive event of streamID=%v, %w", streamID, err)
+		}
+
+		n++
+		from = evt.OriginalEvent().EventNumber + 1
+
+		// the linked event may have been deleted
+		if evt.Event == nil {
+			continue
+		}
+
+		a, err := article(evt.Event)
+		if err != nil {
+			return nil, n, from, err
+		}
+		aa = append(aa, a)
+	}
+}
+
 func (q Queue) loopStream(stream *esdb.Subscription, c chan<- newsReader.Article) {
+	defer close(c)
 	for {
 		evt := stream.Recv()
+		if evt.SubscriptionDropped != nil {
+			q.log.Infow("subscription dropped", "method", "loopStream", "errMsg", evt.SubscriptionDropped.Error)
+			return
+		}
+		// the linked event may have been deleted
+		if evt.EventAppeared == nil || evt.EventAppeared.Event == nil {
+			continue
+		}
 
-		var a newsReader.Article
-		err := json.Unmarshal(evt.EventAppeared.Event.Data, &a)
+		a, err := article(evt.EventAppeared.Event)
 		if err != nil {
 			q.log.Errorw("could not unmarshal article", "method", "loopStream", "errMsg", err)
-			close(c)
 			return
 		}
 
@@ -103,3 +364,23 @@ func (q Queue) loopStream(stream *esdb.Subscription, c chan<- newsReader.Article
 		c <- a
 	}
 }
+
+func article(evt *esdb.RecordedEvent) (newsReader.Article, error) {
+	var a newsReader.Article
+	err := json.Unmarshal(evt.Data, &a)
+	if err != nil {
+		return newsReader.Article{}, fmt.Errorf("could not unmarshal event from streamID=%v, %w", evt.StreamID, err)
+	}
+
+	if len(evt.UserMetadata) != 0 {
+		err = json.Unmarshal(evt.UserMetadata, &a.Meta)
+		if err != nil {
+			return newsReader.Article{}, fmt.Errorf("could not unmarshal metadata from streamID=%v, %w", evt.StreamID, err)
+		}
+	}
+
+	a.Meta.EventID = evt.EventID.String()
+	a.Meta.Recorded = evt.CreatedDate
+	a.Version = evt.EventNumber + 1
+	return a, nil
+}
@@ -0,0 +1,106 @@
+package eventStore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/esdb"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+// fakeStream serves events like esdb.Client.ReadStream: an unknown stream is not found and reading past the
+// end of an existing stream returns the io.EOF of its first Recv.
+type fakeStream struct {
+	events []*esdb.ResolvedEvent
+}
+
+func (f fakeStream) read(_ context.Context, _ string, opts esdb.ReadStreamOptions, count uint64) (events, error) {
+	if len(f.events) == 0 {
+		return nil, esdb.ErrStreamNotFound
+	}
+	var from uint64
+	if r, ok := opts.From.(esdb.StreamRevision); ok {
+		from = r.Value
+	}
+	if from >= uint64(len(f.events)) {
+		return nil, io.EOF
+	}
+	to := from + count
+	if to > uint64(len(f.events)) {
+		to = uint64(len(f.events))
+	}
+	return &sliceEvents{events: f.events[from:to]}, nil
+}
+
+type sliceEvents struct {
+	events []*esdb.ResolvedEvent
+}
+
+func (s *sliceEvents) Recv() (*esdb.ResolvedEvent, error) {
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	evt := s.events[0]
+	s.events = s.events[1:]
+	return evt, nil
+}
+
+func (s *sliceEvents) Close() {}
+
+func TestQueueReplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		numEvents int
+	}{
+		{name: "no stream", numEvents: 0},
+		{name: "partial batch", numEvents: 5},
+		{name: "multiple of batch size", numEvents: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var f fakeStream
+				for i := 0; i < tt.numEvents; i++ {
+					data, err := json.Marshal(newsReader.Article{ID: fmt.Sprintf("article-%d", i)})
+					if err != nil {
+						t.Fatalf("could not marshal article, %v", err)
+					}
+					evt := &esdb.RecordedEvent{EventID: uuid.Must(uuid.NewV4()), EventNumber: uint64(i), Data: data}
+					f.events = append(f.events, &esdb.ResolvedEvent{Event: evt})
+				}
+
+				q := Queue{read: f.read, log: zap.NewNop().Sugar(), batchSize: 3, timeout: time.Second}
+				c := make(chan newsReader.Article)
+				var position uint64
+				errC := make(chan error, 1)
+				go func() {
+					var err error
+					position, err = q.ReplayPosition("collected", c)
+					errC <- err
+				}()
+
+				var got []string
+				for a := range c {
+					got = append(got, a.ID)
+				}
+				if err := <-errC; err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				// consuming continues after the last replayed event
+				if position != uint64(tt.numEvents) {
+					t.Errorf("want position=%v, got %v", tt.numEvents, position)
+				}
+				if len(got) != tt.numEvents {
+					t.Errorf("want %v articles, got %v", tt.numEvents, got)
+				}
+			},
+		)
+	}
+}
@@ -100,3 +100,66 @@ func TestPublisher(t *testing.T) {
 		)
 	}
 }
+
+func TestConsumerLatest(t *testing.T) {
+	tests := []struct {
+		Name string
+
+		LatestFn func(id string) (newsReader.Article, string, error)
+
+		wantPending bool
+		wantErr     bool
+	}{
+		{
+			Name: "pending",
+			LatestFn: func(id string) (newsReader.Article, string, error) {
+				return newsReader.Article{ID: id}, "collected", nil
+			},
+			wantPending: true,
+			wantErr:     false,
+		},
+		{
+			Name: "moved on",
+			LatestFn: func(id string) (newsReader.Article, string, error) {
+				return newsReader.Article{ID: id}, "preprocessed", nil
+			},
+			wantPending: false,
+			wantErr:     false,
+		},
+		{
+			Name: "queue error",
+			LatestFn: func(id string) (newsReader.Article, string, error) {
+				return newsReader.Article{}, "", fmt.Errorf("some queue error")
+			},
+			wantPending: false,
+			wantErr:     true,
+		},
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	logger := zapper.Sugar()
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				q := &mock.Queue{LatestFn: test.LatestFn}
+				c := eventStore.NewConsumer(q, "collected", logger)
+
+				_, pending, err := c.Latest("article-1")
+
+				if (err != nil) != test.wantErr {
+					t.Fatalf("want Latest to return error=%v, got %v", test.wantErr, err)
+				}
+				if pending != test.wantPending {
+					t.Fatalf("want pending=%v got %v", test.wantPending, pending)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,140 @@
+package newsReader
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// Field is an Article field named by its json key.
+type Field string
+
+const (
+	FieldID            Field = "id"
+	FieldAuthor        Field = "author"
+	FieldBody          Field = "body"
+	FieldTitle         Field = "title"
+	FieldCreated       Field = "created"
+	FieldCollected     Field = "collected"
+	FieldUrl           Field = "url"
+	FieldSummary       Field = "summary"
+	FieldSummaryMethod Field = "summaryMethod"
+	FieldTags          Field = "tags"
+	FieldPers          Field = "pers"
+	FieldLocs          Field = "locs"
+	FieldOrgs          Field = "orgs"
+	FieldKeywords      Field = "keywords"
+	FieldStory         Field = "story"
+	FieldDuplicateOf   Field = "duplicateOf"
+	FieldSimilarity    Field = "similarity"
+)
+
+// allFields are read and written by Processors which do not declare their fields.
+var allFields = []Field{
+	FieldID, FieldAuthor, FieldBody, FieldTitle, FieldCreated, FieldCollected, FieldUrl, FieldSummary,
+	FieldSummaryMethod, FieldTags, FieldPers, FieldLocs, FieldOrgs, FieldKeywords, FieldStory, FieldDuplicateOf,
+	FieldSimilarity,
+}
+
+// copyField sets f of dst to the value of f in src.
+func copyField(dst *Article, src Article, f Field) {
+	switch f {
+	case FieldID:
+		dst.ID = src.ID
+	case FieldAuthor:
+		dst.Author = src.Author
+	case FieldBody:
+		dst.Body = src.Body
+	case FieldTitle:
+		dst.Title = src.Title
+	case FieldCreated:
+		dst.Created = src.Created
+	case FieldCollected:
+		dst.Collected = src.Collected
+	case FieldUrl:
+		dst.Url = src.Url
+	case FieldSummary:
+		dst.Summary = src.Summary
+	case FieldSummaryMethod:
+		dst.SummaryMethod = src.SummaryMethod
+	case FieldTags:
+		dst.Tags = src.Tags
+	case FieldPers:
+		dst.Pers = src.Pers
+	case FieldLocs:
+		dst.Locs = src.Locs
+	case FieldOrgs:
+		dst.Orgs = src.Orgs
+	case FieldKeywords:
+		dst.Keywords = src.Keywords
+	case FieldStory:
+		dst.Story = src.Story
+	case FieldDuplicateOf:
+		dst.DuplicateOf = src.DuplicateOf
+	case FieldSimilarity:
+		dst.Similarity = src.Similarity
+	}
+}
+
+// empty returns true if f of a is empty.
+func empty(a Article, f Field) bool {
+	switch f {
+	case FieldID:
+		return len(a.ID) == 0
+	case FieldAuthor:
+		return len(a.Author) == 0
+	case FieldBody:
+		return len(a.Body) == 0
+	case FieldTitle:
+		return len(a.Title) == 0
+	case FieldCreated:
+		return len(a.Created) == 0
+	case FieldCollected:
+		return len(a.Collected) == 0
+	case FieldUrl:
+		return len(a.Url) == 0
+	case FieldSummary:
+		return len(a.Summary) == 0
+	case FieldSummaryMethod:
+		return len(a.SummaryMethod) == 0
+	case FieldTags:
+		return len(a.Tags) == 0
+	case FieldPers:
+		return len(a.Pers) == 0
+	case FieldLocs:
+		return len(a.Locs) == 0
+	case FieldOrgs:
+		return len(a.Orgs) == 0
+	case FieldKeywords:
+		return len(a.Keywords) == 0
+	case FieldStory:
+		return len(a.Story) == 0
+	case FieldDuplicateOf:
+		return len(a.DuplicateOf) == 0
+	case FieldSimilarity:
+		return a.Similarity == 0
+	}
+	return true
+}
+
+// input marshals the fields ff of a.
+func input(a Article, ff []Field) ([]byte, error) {
+	var in Article
+	for _, f := range ff {
+		copyField(&in, a, f)
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal input of article=%s, %w", a.ID, err)
+	}
+	return b, nil
+}
+
+// digest hashes the fields ff of a, empty if they cannot be marshalled.
+func digest(a Article, ff []Field) string {
+	b, err := input(a, ff)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
@@ -0,0 +1,53 @@
+package newsReader
+
+import (
+	"unicode/utf8"
+)
+
+// minStopwordShare is the share of stopwords among the words of a German text.
+const minStopwordShare = 0.2
+
+// MinBodyLength selects articles whose body has at least n characters.
+func MinBodyLength(n int) Filter {
+	return func(a Article) bool {
+		return utf8.RuneCountInString(a.Body) >= n
+	}
+}
+
+// Missing selects articles where any of ff is empty or whose fields read by p changed since p processed
+// them, e.g. to skip NER on articles whose entities are present and whose body is unchanged.
+func Missing(p Processor, ff ...Field) Filter {
+	return func(a Article) bool {
+		for _, f := range ff {
+			if empty(a, f) {
+				return true
+			}
+		}
+		in, ok := a.Meta.Inputs[p.Name()]
+		return !ok || in != digest(a, reads(p))
+	}
+}
+
+// German selects articles whose body is German, guessed by the share of German stopwords among its words.
+func German() Filter {
+	return func(a Article) bool {
+		ww := words(a.Body)
+		if len(ww) == 0 {
+			return false
+		}
+		n := 0
+		for _, w := range ww {
+			if stopwords[w] {
+				n++
+			}
+		}
+		return float64(n)/float64(len(ww)) >= minStopwordShare
+	}
+}
+
+// Not selects articles not selected by f.
+func Not(f Filter) Filter {
+	return func(a Article) bool {
+		return !f(a)
+	}
+}
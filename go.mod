@@ -6,6 +6,7 @@ require (
 	github.com/EventStore/EventStore-Client-Go v1.0.2
 	github.com/PuerkitoBio/goquery v1.5.1
 	github.com/gocolly/colly/v2 v2.1.0
+	github.com/gofrs/uuid v3.3.0+incompatible
 	github.com/joho/godotenv v1.4.0
 	github.com/opensearch-project/opensearch-go v1.1.0
 	go.uber.org/zap v1.19.1
@@ -18,7 +19,6 @@ require (
 	github.com/antchfx/xmlquery v1.2.4 // indirect
 	github.com/antchfx/xpath v1.1.8 // indirect
 	github.com/gobwas/glob v0.2.3 // indirect
-	github.com/gofrs/uuid v3.3.0+incompatible // indirect
 	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
 	github.com/golang/mock v1.6.0 // indirect
 	github.com/golang/protobuf v1.5.0 // indirect
@@ -0,0 +1,263 @@
+package newsReader
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+// maxPhraseLen is the max number of words of a keyphrase.
+const maxPhraseLen = 3
+
+// maxDocs is the number of articles in the idf table above which it is aged, see age.
+const maxDocs = 50000
+
+// KeywordExtractor is a Processor filling Article.Keywords with the n highest scoring keyphrases.
+// Candidate phrases are runs of up to maxPhraseLen words between stopwords and punctuation, scored
+// by their frequency times the mean idf of their words. The idf table is maintained from all
+// processed articles, every article is learned once, e.g. not again when re-crawled.
+type KeywordExtractor struct {
+	n   int
+	log *zap.SugaredLogger
+
+	mu   sync.RWMutex
+	df   map[string]int
+	docs int
+	// learned are the IDs of the articles in df, ids in the order they have been learned.
+	learned map[string]bool
+	ids     []string
+}
+
+func NewKeywordExtractor(n int, l *zap.SugaredLogger) *KeywordExtractor {
+	return &KeywordExtractor{n: n, log: l, df: make(map[string]int), learned: make(map[string]bool)}
+}
+
+func (k *KeywordExtractor) Name() string {
+	return "Keywords"
+}
+
+func (k *KeywordExtractor) Version() string {
+	return "phrase-idf-1"
+}
+
+func (k *KeywordExtractor) Reads() []Field {
+	return []Field{FieldTitle, FieldBody}
+}
+
+func (k *KeywordExtractor) Writes() []Field {
+	return []Field{FieldKeywords}
+}
+
+func (k *KeywordExtractor) Process(a Article) (Article, error) {
+	k.learn(a)
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	a.Keywords = k.extract(a)
+	k.log.Debugw("extracted keywords", "method", "Process", "articleID", a.ID, "keywords", a.Keywords)
+	return a, nil
+}
+
+// Warm adds all articles of src to the idf table, e.g. all preprocessed articles after a restart.
+func (k *KeywordExtractor) Warm(src Source) error {
+	return Warm(src, k.log, k)
+}
+
+// Restore adds a to the idf table.
+func (k *KeywordExtractor) Restore(a Article) error {
+	k.learn(a)
+	return nil
+}
+
+// learn counts the words of a in the document frequencies unless a has been learned before.
+func (k *KeywordExtractor) learn(a Article) {
+	k.mu.RLock()
+	learned := k.learned[a.ID]
+	k.mu.RUnlock()
+	if learned {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, w := range words(a.Title + " " + a.Body) {
+		seen[w] = true
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.learned[a.ID] {
+		return
+	}
+	k.learned[a.ID] = true
+	k.ids = append(k.ids, a.ID)
+	k.docs++
+	for w := range seen {
+		k.df[w]++
+	}
+
+	if k.docs > maxDocs {
+		k.age()
+	}
+}
+
+// age halves all counts of the idf table, so recent articles weigh more and rare words are dropped.
+// The older half of the learned articles is forgotten and learned again if processed again, callers
+// must hold k.mu.
+func (k *KeywordExtractor) age() {
+	k.docs /= 2
+	halve(k.df)
+
+	old := len(k.ids) / 2
+	for _, id := range k.ids[:old] {
+		delete(k.learned, id)
+	}
+	k.ids = append([]string(nil), k.ids[old:]...)
+
+	k.log.Infow("aged idf table", "method", "learn", "numArticles", k.docs, "numWords", len(k.df))
+}
+
+// halve halves all counts of df and drops the words whose count drops to zero.
+func halve(df map[string]int) {
+	for w, n := range df {
+		if n/2 == 0 {
+			delete(df, w)
+			continue
+		}
+		df[w] = n / 2
+	}
+}
+
+func (k *KeywordExtractor) idf(w string) float64 {
+	return math.Log(float64(1+k.docs)/float64(1+k.df[w])) + 1
+}
+
+type phrase struct {
+	key   string
+	form  string
+	words []string
+	count int
+	score float64
+}
+
+// extract returns the keyphrases of a, callers must hold k.mu.
+func (k *KeywordExtractor) extract(a Article) []string {
+	pp := make(map[string]*phrase)
+	var order []*phrase
+	add := func(text string, weight int) {
+		for _, p := range candidates(text) {
+			key := strings.ToLower(strings.Join(p, " "))
+			if cur, ok := pp[key]; ok {
+				cur.count += weight
+				continue
+			}
+			cur := &phrase{key: key, form: strings.Join(p, " "), words: strings.Fields(key), count: weight}
+			pp[key] = cur
+			order = append(order, cur)
+		}
+	}
+	add(a.Title, 2)
+	add(a.Body, 1)
+
+	for _, p := range order {
+		var idf float64
+		for _, w := range p.words {
+			idf += k.idf(w)
+		}
+		// longer phrases are more descriptive than their single words
+		p.score = float64(p.count) * idf / float64(len(p.words)) * (1 + 0.5*float64(len(p.words)-1))
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].score > order[j].score })
+
+	var keywords []string
+	covered := make(map[string]bool)
+	for _, p := range order {
+		if len(keywords) == k.n {
+			break
+		}
+		if len(p.words) == 1 && covered[p.key] {
+			continue
+		}
+		keywords = append(keywords, p.form)
+		for _, w := range p.words {
+			covered[w] = true
+		}
+	}
+	return keywords
+}
+
+// candidates splits text at punctuation and stopwords into phrases of up to maxPhraseLen words,
+// longer runs are split into single words. As German noun phrases end with a capitalized noun, a
+// phrase ends at every capitalized word followed by a lower case word and lower case words without
+// a following capitalized word, mostly verbs and adverbs, are dropped. Lower case words in front of
+// a noun are kept if they carry an adjective ending.
+func candidates(text string) [][]string {
+	var pp [][]string
+	var run []string
+	flush := func() {
+		// drop trailing lower case words and leading ones which are no inflected adjectives
+		for len(run) != 0 && !capitalized(run[len(run)-1]) {
+			run = run[:len(run)-1]
+		}
+		for len(run) != 0 && !capitalized(run[0]) && !adjective(run[0]) {
+			run = run[1:]
+		}
+		if len(run) <= maxPhraseLen {
+			if len(run) != 0 {
+				pp = append(pp, run)
+			}
+		} else {
+			for _, w := range run {
+				if capitalized(w) {
+					pp = append(pp, []string{w})
+				}
+			}
+		}
+		run = nil
+	}
+
+	for _, clause := range strings.FieldsFunc(text, unicode.IsPunct) {
+		for _, w := range fields(clause) {
+			if stopwords[strings.ToLower(w)] || len([]rune(w)) < 3 || isNumber(w) {
+				flush()
+				continue
+			}
+			if len(run) != 0 && capitalized(run[len(run)-1]) && !capitalized(w) {
+				flush()
+			}
+			run = append(run, w)
+		}
+		flush()
+	}
+	return pp
+}
+
+func capitalized(w string) bool {
+	for _, r := range w {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func adjective(w string) bool {
+	for _, suffix := range []string{"e", "en", "er", "es", "em"} {
+		if strings.HasSuffix(w, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsNumber(r) {
+			return false
+		}
+	}
+	return true
+}
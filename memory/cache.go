@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"container/list"
+	"sync"
+
+	"newsReader"
+)
+
+// ResultCache is an in-memory newsReader.ResultCache evicting the least recently used result beyond its size.
+type ResultCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type entry struct {
+	key string
+	a   newsReader.Article
+}
+
+func NewResultCache(size int) *ResultCache {
+	return &ResultCache{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *ResultCache) Load(key string) (newsReader.Article, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return newsReader.Article{}, newsReader.ErrNotFound
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(entry).a, nil
+}
+
+func (c *ResultCache) Save(key string, a newsReader.Article) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value = entry{key: key, a: a}
+		c.order.MoveToFront(e)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(entry{key: key, a: a})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(entry).key)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"math/bits"
+	"sync"
+
+	"newsReader"
+)
+
+// bands splits a hash into 8 bit bands. Hashes within a distance of bands-1 bits share at least one band.
+const bands = 8
+
+// FingerprintStore is an in-memory newsReader.FingerprintStore indexing fingerprints by the bands of their hash.
+type FingerprintStore struct {
+	mu    sync.RWMutex
+	fps   map[string]newsReader.Fingerprint
+	index [bands]map[uint8]map[string]bool
+}
+
+func NewFingerprintStore() *FingerprintStore {
+	s := &FingerprintStore{fps: make(map[string]newsReader.Fingerprint)}
+	for i := range s.index {
+		s.index[i] = make(map[uint8]map[string]bool)
+	}
+	return s
+}
+
+func band(hash uint64, i int) uint8 {
+	return uint8(hash >> (8 * uint(i)))
+}
+
+func (s *FingerprintStore) Nearest(hash uint64, maxDistance int, articleID string) (newsReader.Fingerprint, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var candidates map[string]bool
+	if maxDistance < bands {
+		candidates = make(map[string]bool)
+		for i := range s.index {
+			for id := range s.index[i][band(hash, i)] {
+				candidates[id] = true
+			}
+		}
+	} else {
+		candidates = make(map[string]bool, len(s.fps))
+		for id := range s.fps {
+			candidates[id] = true
+		}
+	}
+
+	var nearest newsReader.Fingerprint
+	found := false
+	min := maxDistance + 1
+	for id := range candidates {
+		if id == articleID {
+			continue
+		}
+		f := s.fps[id]
+		d := bits.OnesCount64(hash ^ f.Hash)
+		// prefer the earliest fingerprint of equal distance
+		if d < min || (d == min && found && f.Time.Before(nearest.Time)) {
+			nearest, min, found = f, d, true
+		}
+	}
+	return nearest, found, nil
+}
+
+func (s *FingerprintStore) Save(f newsReader.Fingerprint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.fps[f.ArticleID]; ok {
+		if old.Time.Before(f.Time) {
+			f.Time = old.Time
+		}
+		for i := range s.index {
+			delete(s.index[i][band(old.Hash, i)], f.ArticleID)
+		}
+	}
+
+	s.fps[f.ArticleID] = f
+	for i := range s.index {
+		b := band(f.Hash, i)
+		if s.index[i][b] == nil {
+			s.index[i][b] = make(map[string]bool)
+		}
+		s.index[i][b][f.ArticleID] = true
+	}
+	return nil
+}
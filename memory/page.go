@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"container/list"
+	"sync"
+
+	"newsReader"
+)
+
+// PageCache is an in-memory newsReader.PageCache evicting the least recently used page beyond its size,
+// e.g. for crawls without a cache dir.
+type PageCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	pages map[string]*list.Element
+}
+
+type pageEntry struct {
+	url string
+	p   newsReader.Page
+}
+
+func NewPageCache(size int) *PageCache {
+	return &PageCache{size: size, order: list.New(), pages: make(map[string]*list.Element)}
+}
+
+func (c *PageCache) Load(url string) (newsReader.Page, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.pages[url]
+	if !ok {
+		return newsReader.Page{}, newsReader.ErrNotFound
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(pageEntry).p, nil
+}
+
+func (c *PageCache) Save(url string, p newsReader.Page) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.pages[url]; ok {
+		e.Value = pageEntry{url: url, p: p}
+		c.order.MoveToFront(e)
+		return nil
+	}
+
+	c.pages[url] = c.order.PushFront(pageEntry{url: url, p: p})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.pages, oldest.Value.(pageEntry).url)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"sync"
+
+	"newsReader"
+)
+
+// Store is an in-memory newsReader.ProjectionStore.
+type Store struct {
+	mu    sync.RWMutex
+	views map[string]newsReader.View
+}
+
+func NewStore() *Store {
+	return &Store{views: make(map[string]newsReader.View)}
+}
+
+func (s *Store) Load(id string) (newsReader.View, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.views[id]
+	if !ok {
+		return newsReader.View{}, newsReader.ErrNotFound
+	}
+
+	history := make([]newsReader.Event, len(v.History))
+	copy(history, v.History)
+	v.History = history
+	return v, nil
+}
+
+func (s *Store) Save(v newsReader.View) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.views[v.Current.ID] = v
+	return nil
+}
@@ -0,0 +1,32 @@
+package newsReader
+
+import "time"
+
+// Metadata describes the event an article was read from or is published as.
+// EventStore picks up $correlationId and $causationId for its system projections.
+type Metadata struct {
+	EventID         string            `json:"eventId"`
+	CorrelationID   string            `json:"$correlationId"`
+	CausationID     string            `json:"$causationId"`
+	Producer        string            `json:"producer"`
+	ProducerVersion string            `json:"producerVersion"`
+	Host            string            `json:"host"`
+	Processors      map[string]string `json:"processors,omitempty"`
+	// Inputs are the digests of the fields every processor read, see Missing.
+	Inputs map[string]string `json:"inputs,omitempty"`
+
+	// Recorded is the time the event has been appended to the queue.
+	Recorded time.Time `json:"-"`
+}
+
+// Versioned is implemented by Processors which can report the version of their model.
+type Versioned interface {
+	Version() string
+}
+
+// Delegating is implemented by Processors delegating an article to one of several processors, e.g. a
+// Fallback. Delegate returns the processor which produced the result, nil if none did, so the version
+// of that processor is recorded.
+type Delegating interface {
+	Delegate(a Article) (Article, Processor, error)
+}
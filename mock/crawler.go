@@ -3,11 +3,16 @@ package mock
 import "newsReader"
 
 type Crawler struct {
+	// CrawlerName defaults to mockCrawler.
+	CrawlerName  string
 	CrawlFn      func() ([]newsReader.Article, error)
 	CrawlInvoked bool
 }
 
 func (c *Crawler) Name() string {
+	if len(c.CrawlerName) != 0 {
+		return c.CrawlerName
+	}
 	return "mockCrawler"
 }
 
@@ -15,3 +20,15 @@ func (c *Crawler) Crawl() ([]newsReader.Article, error) {
 	c.CrawlInvoked = true
 	return c.CrawlFn()
 }
+
+type StreamCrawler struct {
+	StreamFn func(c chan<- newsReader.Article) error
+}
+
+func (s *StreamCrawler) Name() string {
+	return "mockStreamCrawler"
+}
+
+func (s *StreamCrawler) Stream(c chan<- newsReader.Article) error {
+	return s.StreamFn(c)
+}
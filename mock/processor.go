@@ -11,7 +11,50 @@ func (p *Processor) Name() string {
 	return "mockProcessor"
 }
 
+func (p *Processor) Version() string {
+	return "mockVersion"
+}
+
 func (p *Processor) Process(a newsReader.Article) (newsReader.Article, error) {
 	p.ProcessInvoked = true
 	return p.ProcessFn(a)
 }
+
+// FieldProcessor is a Processor declaring the fields it reads and writes.
+type FieldProcessor struct {
+	ProcessorName string
+	ReadFields    []newsReader.Field
+	WriteFields   []newsReader.Field
+	ModelVersion  string
+	ProcessFn     func(a newsReader.Article) (newsReader.Article, error)
+}
+
+func (p *FieldProcessor) Name() string {
+	return p.ProcessorName
+}
+
+func (p *FieldProcessor) Version() string {
+	return p.ModelVersion
+}
+
+func (p *FieldProcessor) Reads() []newsReader.Field {
+	return p.ReadFields
+}
+
+func (p *FieldProcessor) Writes() []newsReader.Field {
+	return p.WriteFields
+}
+
+func (p *FieldProcessor) Process(a newsReader.Article) (newsReader.Article, error) {
+	return p.ProcessFn(a)
+}
+
+type Warmer struct {
+	RestoreFn      func(a newsReader.Article) error
+	RestoreInvoked bool
+}
+
+func (w *Warmer) Restore(a newsReader.Article) error {
+	w.RestoreInvoked = true
+	return w.RestoreFn(a)
+}
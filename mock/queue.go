@@ -12,9 +12,37 @@ func (p *Publisher) Publish(a newsReader.Article) error {
 	return p.PublishFn(a)
 }
 
+type BatchPublisher struct {
+	Publisher
+
+	FailuresC chan newsReader.PublishError
+
+	CloseFn      func() error
+	CloseInvoked bool
+}
+
+func (p *BatchPublisher) Failures() <-chan newsReader.PublishError {
+	return p.FailuresC
+}
+
+func (p *BatchPublisher) Close() error {
+	p.CloseInvoked = true
+	return p.CloseFn()
+}
+
 type Consumer struct {
 	ConsumeFn      func(c chan<- newsReader.Article)
 	ConsumeInvoked bool
+
+	LatestFn      func(id string) (newsReader.Article, bool, error)
+	LatestInvoked bool
+
+	// StopFn is called by Stop if set.
+	StopFn func()
+
+	// ReplayFn replays into c if set, Replay closes c without articles otherwise.
+	ReplayFn      func(c chan<- newsReader.Article) error
+	ReplayInvoked bool
 }
 
 func (co *Consumer) Consume(c chan<- newsReader.Article) {
@@ -22,12 +50,38 @@ func (co *Consumer) Consume(c chan<- newsReader.Article) {
 	co.ConsumeFn(c)
 }
 
+func (co *Consumer) Latest(id string) (newsReader.Article, bool, error) {
+	co.LatestInvoked = true
+	return co.LatestFn(id)
+}
+
+func (co *Consumer) Replay(c chan<- newsReader.Article) error {
+	co.ReplayInvoked = true
+	if co.ReplayFn == nil {
+		close(c)
+		return nil
+	}
+	return co.ReplayFn(c)
+}
+
+func (co *Consumer) Stop() {
+	if co.StopFn != nil {
+		co.StopFn()
+	}
+}
+
 type Queue struct {
 	PublishFn      func(a newsReader.Article, eType string) error
 	PublishInvoked bool
 
 	ConsumeFn      func(eType string, c chan<- newsReader.Article)
 	ConsumeInvoked bool
+
+	LatestFn      func(id string) (newsReader.Article, string, error)
+	LatestInvoked bool
+
+	ReplayFn      func(eType string, c chan<- newsReader.Article) error
+	ReplayInvoked bool
 }
 
 func (q *Queue) Publish(a newsReader.Article, eType string) error {
@@ -39,3 +93,33 @@ func (q *Queue) Consume(eType string, c chan<- newsReader.Article) {
 	q.ConsumeInvoked = true
 	q.ConsumeFn(eType, c)
 }
+
+func (q *Queue) Latest(id string) (newsReader.Article, string, error) {
+	q.LatestInvoked = true
+	return q.LatestFn(id)
+}
+
+func (q *Queue) Replay(eType string, c chan<- newsReader.Article) error {
+	q.ReplayInvoked = true
+	return q.ReplayFn(eType, c)
+}
+
+type Source struct {
+	ReplayFn      func(c chan<- newsReader.Article) error
+	ReplayInvoked bool
+}
+
+func (s *Source) Replay(c chan<- newsReader.Article) error {
+	s.ReplayInvoked = true
+	return s.ReplayFn(c)
+}
+
+type StoryPublisher struct {
+	PublishStoryFn      func(s newsReader.Story, eType string) error
+	PublishStoryInvoked bool
+}
+
+func (p *StoryPublisher) PublishStory(s newsReader.Story, eType string) error {
+	p.PublishStoryInvoked = true
+	return p.PublishStoryFn(s, eType)
+}
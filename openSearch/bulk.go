@@ -0,0 +1,201 @@
+package openSearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"newsReader"
+)
+
+// BulkPublisher buffers articles and indexes them via the _bulk API once maxDocs articles or
+// maxBytes are buffered, or interval passed since the last flush. Like Publisher it indexes articles
+// with their stream revision as external version and treats version conflicts as success.
+type BulkPublisher struct {
+	Publisher
+	maxDocs  int
+	maxBytes int
+	interval time.Duration
+	items    chan item
+	failures chan newsReader.PublishError
+	done     chan struct{}
+}
+
+type item struct {
+	article newsReader.Article
+	doc     []byte
+}
+
+// NewBulkPublisher returns a BulkPublisher indexing into the index of p. Publish must not be called after Close.
+func NewBulkPublisher(p *Publisher, maxDocs, maxBytes int, interval time.Duration) *BulkPublisher {
+	bp := &BulkPublisher{
+		Publisher: *p,
+		maxDocs:   maxDocs,
+		maxBytes:  maxBytes,
+		interval:  interval,
+		items:     make(chan item, maxDocs),
+		failures:  make(chan newsReader.PublishError, maxDocs),
+		done:      make(chan struct{}),
+	}
+
+	go bp.loop()
+	return bp
+}
+
+func (bp *BulkPublisher) Publish(a newsReader.Article) error {
+	bp.log.Debugw("buffer article", "method", "Publish", "articleID", a.ID)
+	b, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("could not marshal article with id=%s, %w", a.ID, err)
+	}
+
+	bp.items <- item{article: a, doc: b}
+	return nil
+}
+
+func (bp *BulkPublisher) Failures() <-chan newsReader.PublishError {
+	return bp.failures
+}
+
+// Close flushes all buffered articles and closes the failures channel.
+func (bp *BulkPublisher) Close() error {
+	close(bp.items)
+	<-bp.done
+	return nil
+}
+
+func (bp *BulkPublisher) loop() {
+	defer close(bp.done)
+	defer close(bp.failures)
+
+	ticker := time.NewTicker(bp.interval)
+	defer ticker.Stop()
+
+	var batch []item
+	body := bytes.Buffer{}
+
+	flush := func() {
+		if len(batch) != 0 {
+			bp.flush(batch, body.Bytes())
+		}
+		batch = nil
+		body.Reset()
+	}
+
+	for {
+		select {
+		case it, ok := <-bp.items:
+			if !ok {
+				flush()
+				return
+			}
+
+			idx, err := bp.index.Name(it.article)
+			if err != nil {
+				bp.failures <- newsReader.PublishError{Article: it.article, Err: err}
+				continue
+			}
+			err = bp.ensure(idx)
+			if err != nil {
+				bp.failures <- newsReader.PublishError{Article: it.article, Err: err}
+				continue
+			}
+
+			action := map[string]interface{}{"_index": idx, "_id": it.article.ID}
+			if it.article.Version > 0 {
+				action["version"] = it.article.Version
+				action["version_type"] = externalVersion
+			}
+			meta, err := json.Marshal(map[string]interface{}{"index": action})
+			if err != nil {
+				bp.failures <- newsReader.PublishError{Article: it.article, Err: err}
+				continue
+			}
+			body.Write(meta)
+			body.WriteByte('\n')
+			body.Write(it.doc)
+			body.WriteByte('\n')
+			batch = append(batch, it)
+
+			if len(batch) >= bp.maxDocs || body.Len() >= bp.maxBytes {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (bp *BulkPublisher) flush(batch []item, body []byte) {
+	bp.log.Infow("flush articles", "method", "flush", "numArticles", len(batch), "bytes", len(body))
+
+	err := bp.bulk(batch, body)
+	if err != nil {
+		for _, it := range batch {
+			bp.failures <- newsReader.PublishError{Article: it.article, Err: err}
+		}
+	}
+}
+
+// bulk indexes body and reports failed items of batch. A returned error applies to the whole batch.
+func (bp *BulkPublisher) bulk(batch []item, body []byte) error {
+	resp, err := opensearchapi.BulkRequest{Body: bytes.NewReader(body)}.Do(context.Background(), bp.client)
+	if err != nil {
+		return fmt.Errorf("could not request bulk of %v articles, %w", len(batch), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("opensearch response status code=%v while bulk publishing %v articles", resp.StatusCode, len(batch))
+	}
+
+	var res bulkResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("could not decode bulk response, %w", err)
+	}
+	if !res.Errors {
+		return nil
+	}
+	if len(res.Items) != len(batch) {
+		return fmt.Errorf("bulk response has %v items for %v articles", len(res.Items), len(batch))
+	}
+
+	for i, it := range res.Items {
+		for _, r := range it {
+			if r.Status < 300 {
+				continue
+			}
+			if r.Status == http.StatusConflict {
+				bp.log.Infow(
+					"skip stale article",
+					"method", "bulk",
+					"articleID", batch[i].article.ID,
+					"version", batch[i].article.Version,
+				)
+				continue
+			}
+			bp.failures <- newsReader.PublishError{
+				Article: batch[i].article,
+				Err:     fmt.Errorf("status=%v, %s: %s", r.Status, r.Error.Type, r.Error.Reason),
+			}
+		}
+	}
+	return nil
+}
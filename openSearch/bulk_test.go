@@ -0,0 +1,187 @@
+package openSearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go"
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+func TestBulkPublisher(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxDocs      int
+		articles     []string
+		status       int
+		failedID     string
+		conflictID   string
+		wantRequests int
+		wantFailed   []string
+	}{
+		{
+			name:         "flush by count",
+			maxDocs:      2,
+			articles:     []string{"aa", "bb", "cc", "dd"},
+			status:       200,
+			wantRequests: 2,
+		},
+		{
+			name:         "flush on close",
+			maxDocs:      10,
+			articles:     []string{"aa", "bb", "cc"},
+			status:       200,
+			wantRequests: 1,
+		},
+		{
+			name:         "document failure",
+			maxDocs:      10,
+			articles:     []string{"aa", "bb"},
+			status:       200,
+			failedID:     "bb",
+			wantRequests: 1,
+			wantFailed:   []string{"bb"},
+		},
+		{
+			name:         "version conflict",
+			maxDocs:      10,
+			articles:     []string{"aa", "bb"},
+			status:       200,
+			conflictID:   "aa",
+			wantRequests: 1,
+		},
+		{
+			name:         "request failure",
+			maxDocs:      10,
+			articles:     []string{"aa", "bb"},
+			status:       500,
+			wantRequests: 1,
+			wantFailed:   []string{"aa", "bb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				requests := 0
+				srv := httptest.NewServer(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							if r.URL.Path == "/" {
+								w.Header().Set("Content-Type", "application/json")
+								_, _ = fmt.Fprint(w, `{"version": {"number": "1.2.4", "distribution": "opensearch"}}`)
+								return
+							}
+							if r.Method == http.MethodPut {
+								// create index and add alias
+								_, _ = fmt.Fprint(w, `{"acknowledged": true}`)
+								return
+							}
+							if r.URL.Path != "/_bulk" {
+								t.Errorf("want bulk request, got path=%s", r.URL.Path)
+								return
+							}
+							requests++
+
+							if tt.status != 200 {
+								w.WriteHeader(tt.status)
+								return
+							}
+
+							var ids []string
+							scanner := bufio.NewScanner(r.Body)
+							for i := 0; scanner.Scan(); i++ {
+								if i%2 == 0 {
+									var meta struct {
+										Index struct {
+											ID          string `json:"_id"`
+											Version     uint64 `json:"version"`
+											VersionType string `json:"version_type"`
+										} `json:"index"`
+									}
+									err := json.Unmarshal(scanner.Bytes(), &meta)
+									if err != nil {
+										t.Errorf("could not decode bulk action, %v", err)
+									}
+									if meta.Index.Version == 0 || meta.Index.VersionType != "external" {
+										t.Errorf("want external version, got %+v", meta.Index)
+									}
+									ids = append(ids, meta.Index.ID)
+								}
+							}
+
+							w.Header().Set("Content-Type", "application/json")
+							_, _ = fmt.Fprintf(w, `{"errors": %v, "items": [`, len(tt.failedID)+len(tt.conflictID) != 0)
+							for i, id := range ids {
+								if i > 0 {
+									_, _ = fmt.Fprint(w, ",")
+								}
+								if id == tt.failedID {
+									_, _ = fmt.Fprintf(w, `{"index": {"_id": %q, "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "bad"}}}`, id)
+									continue
+								}
+								if id == tt.conflictID {
+									_, _ = fmt.Fprintf(w, `{"index": {"_id": %q, "status": 409, "error": {"type": "version_conflict_engine_exception", "reason": "stale"}}}`, id)
+									continue
+								}
+								_, _ = fmt.Fprintf(w, `{"index": {"_id": %q, "status": 201}}`, id)
+							}
+							_, _ = fmt.Fprint(w, "]}")
+						},
+					),
+				)
+				defer srv.Close()
+
+				client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{srv.URL}})
+				if err != nil {
+					t.Fatalf("could not create client")
+				}
+
+				p := &Publisher{
+					client:  client,
+					index:   TimedIndex("articles", "2006.01", Collected, "articles-read"),
+					written: &sync.Map{},
+					log:     zap.NewNop().Sugar(),
+				}
+				bp := NewBulkPublisher(p, tt.maxDocs, 1<<20, time.Hour)
+
+				var failed []string
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					for f := range bp.Failures() {
+						failed = append(failed, f.Article.ID)
+					}
+				}()
+
+				for i, id := range tt.articles {
+					err = bp.Publish(newsReader.Article{ID: id, Collected: "2022-01-14T18:35:00Z", Version: uint64(i + 1)})
+					if err != nil {
+						t.Fatalf("want no publish error, got=%v", err)
+					}
+				}
+				err = bp.Close()
+				if err != nil {
+					t.Fatalf("want no close error, got=%v", err)
+				}
+				<-done
+
+				if requests != tt.wantRequests {
+					t.Errorf("want requests=%v, got=%v", tt.wantRequests, requests)
+				}
+				sort.Strings(failed)
+				if fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) {
+					t.Errorf("want failed=%v, got=%v", tt.wantFailed, failed)
+				}
+			},
+		)
+	}
+}
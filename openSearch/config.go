@@ -0,0 +1,160 @@
+package openSearch
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go"
+)
+
+// Config configures the connection to an OpenSearch cluster. At most one of basic auth, APIKey and
+// Token may be set.
+type Config struct {
+	Addr string
+	User string
+	Pwd  string
+	// APIKey is sent as "Authorization: ApiKey <key>".
+	APIKey string
+	// Token is sent as "Authorization: Bearer <token>".
+	Token string
+	// CACert is the path to a PEM bundle of CAs to verify the cluster with, the system pool is used if empty.
+	CACert string
+	// Cert and Key are paths to a PEM client certificate and key.
+	Cert string
+	Key  string
+	// ServerName overrides the host name the server certificate is verified against.
+	ServerName string
+	// Insecure skips verifying the server certificate, only meant for local development.
+	Insecure bool
+}
+
+// ConfigFromEnv reads the config from the environment:
+//
+//	OS_ADDR (required), OS_USER, OS_PWD, OS_API_KEY, OS_TOKEN,
+//	OS_CA_CERT, OS_CLIENT_CERT, OS_CLIENT_KEY, OS_SERVER_NAME, OS_INSECURE.
+func ConfigFromEnv() (Config, error) {
+	addr, ok := os.LookupEnv("OS_ADDR")
+	if !ok {
+		return Config{}, errors.New("could not read opensearch addr from env")
+	}
+
+	insecure := false
+	if s, ok := os.LookupEnv("OS_INSECURE"); ok {
+		var err error
+		insecure, err = strconv.ParseBool(s)
+		if err != nil {
+			return Config{}, fmt.Errorf("could not parse OS_INSECURE=%s, %w", s, err)
+		}
+	}
+
+	return Config{
+		Addr:       addr,
+		User:       os.Getenv("OS_USER"),
+		Pwd:        os.Getenv("OS_PWD"),
+		APIKey:     os.Getenv("OS_API_KEY"),
+		Token:      os.Getenv("OS_TOKEN"),
+		CACert:     os.Getenv("OS_CA_CERT"),
+		Cert:       os.Getenv("OS_CLIENT_CERT"),
+		Key:        os.Getenv("OS_CLIENT_KEY"),
+		ServerName: os.Getenv("OS_SERVER_NAME"),
+		Insecure:   insecure,
+	}, nil
+}
+
+func (c Config) validate() error {
+	if len(c.Addr) == 0 {
+		return errors.New("opensearch addr must not be empty")
+	}
+
+	methods := 0
+	for _, set := range []bool{len(c.User) != 0 || len(c.Pwd) != 0, len(c.APIKey) != 0, len(c.Token) != 0} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return errors.New("only one of basic auth, api key and token may be configured")
+	}
+
+	if (len(c.Cert) == 0) != (len(c.Key) == 0) {
+		return errors.New("client certificate and key must be configured together")
+	}
+	return nil
+}
+
+func (c Config) header() http.Header {
+	h := http.Header{}
+	switch {
+	case len(c.APIKey) != 0:
+		h.Set("Authorization", fmt.Sprintf("ApiKey %s", c.APIKey))
+	case len(c.Token) != 0:
+		h.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	}
+	return h
+}
+
+func (c Config) tlsConfig() (*tls.Config, error) {
+	t := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.Insecure,
+	}
+
+	if len(c.CACert) != 0 {
+		b, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca cert=%s, %w", c.CACert, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in ca cert=%s", c.CACert)
+		}
+		t.RootCAs = pool
+	}
+
+	if len(c.Cert) != 0 {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client cert=%s, %w", c.Cert, err)
+		}
+		t.Certificates = []tls.Certificate{cert}
+	}
+
+	return t, nil
+}
+
+func (c Config) client() (*opensearch.Client, error) {
+	err := c.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return opensearch.NewClient(
+		opensearch.Config{
+			Addresses: []string{
+				fmt.Sprintf("https://%s", c.Addr),
+			},
+			Username: c.User,
+			Password: c.Pwd,
+			Header:   c.header(),
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost:   10,
+				ResponseHeaderTimeout: time.Second * 20,
+				DialContext:           (&net.Dialer{Timeout: time.Second * 20}).DialContext,
+				TLSClientConfig:       t,
+			},
+		},
+	)
+}
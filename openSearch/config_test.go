@@ -0,0 +1,97 @@
+package openSearch
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigClient(t *testing.T) {
+	var auth string
+	srv := httptest.NewTLSServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, `{"version": {"number": "1.2.4", "distribution": "opensearch"}}`)
+			},
+		),
+	)
+	defer srv.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	err := os.WriteFile(ca, b, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := strings.TrimPrefix(srv.URL, "https://")
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantAuth string
+		wantErr  bool
+	}{
+		{
+			name:    "unknown ca",
+			cfg:     Config{Addr: addr, User: "admin", Pwd: "admin"},
+			wantErr: true,
+		},
+		{
+			name:     "insecure",
+			cfg:      Config{Addr: addr, User: "admin", Pwd: "admin", Insecure: true},
+			wantAuth: "Basic YWRtaW46YWRtaW4=",
+		},
+		{
+			name:     "ca bundle with token",
+			cfg:      Config{Addr: addr, Token: "tkn", CACert: ca},
+			wantAuth: "Bearer tkn",
+		},
+		{
+			name:     "ca bundle with server name and api key",
+			cfg:      Config{Addr: addr, APIKey: "key", CACert: ca, ServerName: "example.com"},
+			wantAuth: "ApiKey key",
+		},
+		{
+			name:    "wrong server name",
+			cfg:     Config{Addr: addr, CACert: ca, ServerName: "other.org"},
+			wantErr: true,
+		},
+		{
+			name:    "two auth methods",
+			cfg:     Config{Addr: addr, User: "admin", Token: "tkn", CACert: ca},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     Config{Addr: addr, Cert: ca, CACert: ca},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				auth = ""
+				client, err := tt.cfg.client()
+				if err == nil {
+					_, err = client.Ping()
+				}
+
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("want error=%v, got %v", tt.wantErr, err)
+				}
+				if auth != tt.wantAuth {
+					t.Errorf("want authorization=%s, got %s", tt.wantAuth, auth)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,289 @@
+package openSearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"newsReader"
+)
+
+// DateField selects the article date a TimedIndex rolls over by.
+type DateField int
+
+const (
+	Collected DateField = iota
+	Created
+)
+
+// Index names the index an article is written to. Every index written to is added to the read alias,
+// so old indices can be snapshotted or deleted while dashboards keep querying the alias.
+type Index struct {
+	prefix string
+	layout string
+	date   DateField
+	alias  string
+}
+
+// FixedIndex writes all articles into the index name, an empty alias adds no read alias.
+func FixedIndex(name, alias string) Index {
+	return Index{prefix: name, alias: alias}
+}
+
+// TimedIndex writes articles into prefix-<date> with date formatted by the go time layout,
+// e.g. "2006.01" for monthly indices. An empty alias adds no read alias.
+func TimedIndex(prefix, layout string, date DateField, alias string) Index {
+	return Index{prefix: prefix, layout: layout, date: date, alias: alias}
+}
+
+// ParseIndex returns a FixedIndex for an empty layout, otherwise a TimedIndex rolling over by
+// the date field "created", the default, or "collected".
+func ParseIndex(name, layout, date, alias string) (Index, error) {
+	if len(layout) == 0 {
+		return FixedIndex(name, alias), nil
+	}
+
+	switch date {
+	case "", "created":
+		return TimedIndex(name, layout, Created, alias), nil
+	case "collected":
+		return TimedIndex(name, layout, Collected, alias), nil
+	default:
+		return Index{}, fmt.Errorf("unknown date field=%s", date)
+	}
+}
+
+// IndexFromEnv reads the index the archiver writes to from OS_INDEX, by default article-1, the optional
+// rollover OS_INDEX_LAYOUT and OS_INDEX_DATE and the read alias OS_ALIAS, by default articles.
+func IndexFromEnv() (Index, error) {
+	name, ok := os.LookupEnv("OS_INDEX")
+	if !ok {
+		name = "article-1"
+	}
+	alias, ok := os.LookupEnv("OS_ALIAS")
+	if !ok {
+		alias = "articles"
+	}
+
+	index, err := ParseIndex(name, os.Getenv("OS_INDEX_LAYOUT"), os.Getenv("OS_INDEX_DATE"), alias)
+	if err != nil {
+		return Index{}, fmt.Errorf("could not parse index=%s, %w", name, err)
+	}
+	return index, index.validate()
+}
+
+// Rebuild returns i writing into indices named name instead, without adding the read alias, so the alias can
+// be swapped to the rebuilt indices at once.
+func (i Index) Rebuild(name string) Index {
+	i.prefix = name
+	i.alias = ""
+	return i
+}
+
+// Alias returns the read alias of i.
+func (i Index) Alias() string {
+	return i.alias
+}
+
+// Name returns the index a is written to, an error if a timed index can not date a.
+func (i Index) Name(a newsReader.Article) (string, error) {
+	if len(i.layout) == 0 {
+		return i.prefix, nil
+	}
+	t, err := i.time(a)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s", i.prefix, t.UTC().Format(i.layout)), nil
+}
+
+// pattern matches all indices named by i.
+func (i Index) pattern() string {
+	if len(i.layout) == 0 {
+		return i.prefix
+	}
+	return fmt.Sprintf("%s-*", i.prefix)
+}
+
+// time returns the date of a to roll over by. Articles without a created date roll over by the time they
+// have been collected, falling back to the time their event has been recorded.
+func (i Index) time(a newsReader.Article) (time.Time, error) {
+	if i.date == Created {
+		t, ok := parseDate(a.Created)
+		if ok {
+			return t, nil
+		}
+	}
+
+	t, ok := parseDate(a.Collected)
+	if ok {
+		return t, nil
+	}
+	if !a.Meta.Recorded.IsZero() {
+		return a.Meta.Recorded, nil
+	}
+	return time.Time{}, fmt.Errorf("could not date article with id=%s, collected=%q", a.ID, a.Collected)
+}
+
+func parseDate(s string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, true
+	}
+	// collected dates used to be written by time.Time.String
+	if len(s) >= 19 {
+		t, err = time.Parse("2006-01-02 15:04:05", s[:19])
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (i Index) validate() error {
+	if len(i.prefix) == 0 {
+		return errors.New("index name must not be empty")
+	}
+	if len(i.alias) != 0 && len(i.layout) == 0 && i.alias == i.prefix {
+		return fmt.Errorf("alias=%s must differ from index name", i.alias)
+	}
+	return nil
+}
+
+// ensure creates the index idx and adds the read alias once per publisher.
+func (p Publisher) ensure(idx string) error {
+	if _, ok := p.written.Load(idx); ok {
+		return nil
+	}
+
+	p.log.Infow("ensure index", "method", "ensure", "index", idx, "alias", p.index.alias)
+	resp, err := opensearchapi.IndicesCreateRequest{Index: idx}.Do(context.Background(), p.client)
+	if err != nil {
+		return fmt.Errorf("could not request create index=%s, %w", idx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() && !exists(resp) {
+		return fmt.Errorf("opensearch response status code=%v while creating index=%s", resp.StatusCode, idx)
+	}
+
+	if len(p.index.alias) != 0 {
+		req := opensearchapi.IndicesPutAliasRequest{Index: []string{idx}, Name: p.index.alias}
+		aResp, err := req.Do(context.Background(), p.client)
+		if err != nil {
+			return fmt.Errorf("could not request alias=%s for index=%s, %w", p.index.alias, idx, err)
+		}
+		defer aResp.Body.Close()
+
+		if aResp.IsError() {
+			return fmt.Errorf(
+				"opensearch response status code=%v while adding alias=%s to index=%s",
+				aResp.StatusCode, p.index.alias, idx,
+			)
+		}
+	}
+
+	p.written.Store(idx, true)
+	return nil
+}
+
+func exists(resp *opensearchapi.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	var res struct {
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&res)
+	return err == nil && res.Error.Type == "resource_already_exists_exception"
+}
+
+// SwapAlias atomically points alias to all indices written by the publisher and removes it from all other indices.
+func (p Publisher) SwapAlias(alias string) error {
+	var written []string
+	p.written.Range(
+		func(k, _ interface{}) bool {
+			written = append(written, k.(string))
+			return true
+		},
+	)
+	if len(written) == 0 {
+		return fmt.Errorf("no index written to swap alias=%s to", alias)
+	}
+	sort.Strings(written)
+
+	p.log.Infow("swap alias", "method", "SwapAlias", "alias", alias, "indices", written)
+
+	current, err := p.aliased(alias)
+	if err != nil {
+		return err
+	}
+
+	type action map[string]map[string]string
+	var actions []action
+	keep := make(map[string]bool)
+	for _, idx := range written {
+		keep[idx] = true
+		actions = append(actions, action{"add": {"index": idx, "alias": alias}})
+	}
+	for _, idx := range current {
+		if keep[idx] {
+			continue
+		}
+		actions = append(actions, action{"remove": {"index": idx, "alias": alias}})
+	}
+
+	b, err := json.Marshal(map[string][]action{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("could not marshal alias actions, %w", err)
+	}
+
+	resp, err := opensearchapi.IndicesUpdateAliasesRequest{Body: bytes.NewReader(b)}.Do(context.Background(), p.client)
+	if err != nil {
+		return fmt.Errorf("could not request update of alias=%s, %w", alias, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("opensearch response status code=%v while updating alias=%s", resp.StatusCode, alias)
+	}
+	return nil
+}
+
+// aliased returns all indices alias points to.
+func (p Publisher) aliased(alias string) ([]string, error) {
+	resp, err := opensearchapi.IndicesGetAliasRequest{Name: []string{alias}}.Do(context.Background(), p.client)
+	if err != nil {
+		return nil, fmt.Errorf("could not request alias=%s, %w", alias, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("opensearch response status code=%v while reading alias=%s", resp.StatusCode, alias)
+	}
+
+	var indices map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&indices)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode alias=%s, %w", alias, err)
+	}
+
+	names := make([]string, 0, len(indices))
+	for idx := range indices {
+		names = append(names, idx)
+	}
+	return names, nil
+}
@@ -0,0 +1,155 @@
+package openSearch
+
+import (
+	"testing"
+	"time"
+
+	"newsReader"
+)
+
+func TestIndexName(t *testing.T) {
+	recorded := time.Date(2021, 12, 24, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		index   Index
+		arg     newsReader.Article
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "fixed",
+			index: FixedIndex("article-1", ""),
+			arg:   newsReader.Article{Collected: "2022-01-14T18:35:00Z"},
+			want:  "article-1",
+		},
+		{
+			name:  "monthly by collected",
+			index: TimedIndex("articles", "2006.01", Collected, "articles-read"),
+			arg:   newsReader.Article{Collected: "2022-01-14T18:35:00Z", Created: "2021-11-01T08:00:00+01:00"},
+			want:  "articles-2022.01",
+		},
+		{
+			name:  "unparsable created falls back to collected",
+			index: TimedIndex("articles", "2006.01", Created, ""),
+			arg:   newsReader.Article{ID: "article-1", Created: "gestern", Collected: "2022-01-14T18:35:00Z", Version: 2},
+			want:  "articles-2022.01",
+		},
+		{
+			name:  "monthly by created",
+			index: TimedIndex("articles", "2006.01", Created, "articles-read"),
+			arg:   newsReader.Article{Collected: "2022-01-14T18:35:00Z", Created: "2021-11-01T08:00:00+01:00"},
+			want:  "articles-2021.11",
+		},
+		{
+			name:  "legacy collected",
+			index: TimedIndex("articles", "2006.01", Collected, ""),
+			arg:   newsReader.Article{Collected: "2022-02-03 15:04:05.999 +0100 CET m=+0.001"},
+			want:  "articles-2022.02",
+		},
+		{
+			name:  "unparsable date falls back to recorded",
+			index: TimedIndex("articles", "2006.01.02", Created, ""),
+			arg:   newsReader.Article{Created: "gestern", Meta: newsReader.Metadata{Recorded: recorded}},
+			want:  "articles-2021.12.24",
+		},
+		{
+			name:    "undated",
+			index:   TimedIndex("articles", "2006.01", Collected, ""),
+			arg:     newsReader.Article{ID: "article-1", Collected: "gestern"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := tt.index.Name(tt.arg)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Name() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("Name() got = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestIndexValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   Index
+		wantErr bool
+	}{
+		{name: "fixed", index: FixedIndex("article-1", "articles"), wantErr: false},
+		{name: "empty name", index: FixedIndex("", ""), wantErr: true},
+		{name: "alias equals index", index: FixedIndex("articles", "articles"), wantErr: true},
+		{name: "timed", index: TimedIndex("articles", "2006.01", Collected, "articles"), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := tt.index.validate()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestIndexFromEnv(t *testing.T) {
+	a := newsReader.Article{Created: "2021-11-01T08:00:00+01:00"}
+
+	index, err := IndexFromEnv()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if name(index, a) != "article-1" || index.Alias() != "articles" {
+		t.Errorf("want default index=article-1 alias=articles, got %v %v", name(index, a), index.Alias())
+	}
+
+	t.Setenv("OS_INDEX", "articles-1")
+	t.Setenv("OS_INDEX_LAYOUT", "2006.01")
+	t.Setenv("OS_INDEX_DATE", "created")
+	index, err = IndexFromEnv()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if name(index, a) != "articles-1-2021.11" {
+		t.Errorf("want monthly index, got %v", name(index, a))
+	}
+
+	rebuilt := index.Rebuild("articles-2")
+	if name(rebuilt, a) != "articles-2-2021.11" || len(rebuilt.Alias()) != 0 {
+		t.Errorf("want rebuilt monthly index without alias, got %v %v", name(rebuilt, a), rebuilt.Alias())
+	}
+
+	t.Setenv("OS_ALIAS", "articles-1")
+	t.Setenv("OS_INDEX_LAYOUT", "")
+	_, err = IndexFromEnv()
+	if err == nil {
+		t.Errorf("want error for alias equal to index")
+	}
+}
+
+func TestParseIndex(t *testing.T) {
+	a := newsReader.Article{Collected: "2022-01-14T18:35:00Z", Created: "2021-11-01T08:00:00+01:00"}
+
+	index, err := ParseIndex("articles", "2006.01", "", "")
+	if err != nil || name(index, a) != "articles-2021.11" {
+		t.Errorf("want rollover by created by default, got %v, %v", name(index, a), err)
+	}
+	_, err = ParseIndex("articles", "2006.01", "published", "")
+	if err == nil {
+		t.Errorf("want error for unknown date field")
+	}
+}
+
+// name returns the index a is written to, empty if it can not be named.
+func name(i Index, a newsReader.Article) string {
+	n, _ := i.Name(a)
+	return n
+}
@@ -3,12 +3,10 @@ package openSearch
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
-	"time"
+	"sync"
 
 	"github.com/opensearch-project/opensearch-go"
 	"github.com/opensearch-project/opensearch-go/opensearchapi"
@@ -16,29 +14,26 @@ import (
 	"newsReader"
 )
 
+// externalVersion indexes articles with their stream revision as document version. OpenSearch rejects
+// versions lower or equal to the indexed one, so replayed or reordered events never overwrite newer
+// article states. Articles without a revision are indexed unconditionally.
+const externalVersion = "external"
+
 type Publisher struct {
-	client *opensearch.Client
-	log    *zap.SugaredLogger
+	client  *opensearch.Client
+	index   Index
+	written *sync.Map
+	log     *zap.SugaredLogger
 }
 
-func NewPublisher(user, pwd, addr string, l *zap.SugaredLogger) (*Publisher, error) {
-	cfg := opensearch.Config{
-		Addresses: []string{
-			fmt.Sprintf("https://%s", addr),
-		},
-		Username: user,
-		Password: pwd,
-		Transport: &http.Transport{
-			MaxIdleConnsPerHost:   10,
-			ResponseHeaderTimeout: time.Second * 20,
-			DialContext:           (&net.Dialer{Timeout: time.Second * 20}).DialContext,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+// NewPublisher connects to the cluster configured by cfg and installs the article index template.
+func NewPublisher(cfg Config, index Index, l *zap.SugaredLogger) (*Publisher, error) {
+	err := index.validate()
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := opensearch.NewClient(cfg)
+	client, err := cfg.client()
 	if err != nil {
 		return nil, err
 	}
@@ -51,21 +46,53 @@ func NewPublisher(user, pwd, addr string, l *zap.SugaredLogger) (*Publisher, err
 		return nil, fmt.Errorf("could not ping opensearch, status code=%v", ping.StatusCode)
 	}
 
-	return &Publisher{client: client, log: l}, nil
+	p := &Publisher{client: client, index: index, written: &sync.Map{}, log: l}
+
+	err = p.InstallTemplate()
+	if err != nil {
+		return nil, err
+	}
+	err = p.CheckMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
 }
 
 func (p Publisher) Publish(a newsReader.Article) error {
-	p.log.Infow("publish article", "method", "Publish", "articleID", a.ID)
+	idx, err := p.index.Name(a)
+	if err != nil {
+		return fmt.Errorf("could not name index of article with id=%s, %w", a.ID, err)
+	}
+	p.log.Infow("publish article", "method", "Publish", "articleID", a.ID, "index", idx)
 	b, err := json.Marshal(a)
 	if err != nil {
 		return fmt.Errorf("could not marshal article with id=%s, %w", a.ID, err)
 	}
 
-	request := opensearchapi.IndexRequest{Index: "article-1", DocumentID: a.ID, Body: bytes.NewReader(b)}
+	err = p.ensure(idx)
+	if err != nil {
+		return err
+	}
+
+	request := opensearchapi.IndexRequest{Index: idx, DocumentID: a.ID, Body: bytes.NewReader(b)}
+	if a.Version > 0 {
+		v := int(a.Version)
+		request.Version = &v
+		request.VersionType = externalVersion
+	}
+
 	resp, err := request.Do(context.Background(), p.client)
 	if err != nil {
 		return fmt.Errorf("could not request publish index request article with id=%s, %w", a.ID, err)
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		p.log.Infow("skip stale article", "method", "Publish", "articleID", a.ID, "version", a.Version)
+		return nil
+	}
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf(
 			"opensearch response status code=%v while publishing article with id=%s", resp.StatusCode, a.ID,
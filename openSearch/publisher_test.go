@@ -0,0 +1,94 @@
+package openSearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opensearch-project/opensearch-go"
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+func TestPublishVersioned(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     uint64
+		status      int
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "newer version",
+			version:     3,
+			status:      201,
+			wantVersion: "3",
+		},
+		{
+			name:        "stale version",
+			version:     2,
+			status:      409,
+			wantVersion: "2",
+		},
+		{
+			name:   "no version",
+			status: 201,
+		},
+		{
+			name:        "index failure",
+			version:     1,
+			status:      400,
+			wantVersion: "1",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				srv := httptest.NewServer(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							w.Header().Set("Content-Type", "application/json")
+							if !strings.Contains(r.URL.Path, "/_doc/") {
+								_, _ = fmt.Fprint(w, `{"version": {"number": "1.2.4", "distribution": "opensearch"}}`)
+								return
+							}
+
+							q := r.URL.Query()
+							if q.Get("version") != tt.wantVersion {
+								t.Errorf("want version=%s, got %s", tt.wantVersion, q.Get("version"))
+							}
+							if len(tt.wantVersion) != 0 && q.Get("version_type") != "external" {
+								t.Errorf("want version_type=external, got %s", q.Get("version_type"))
+							}
+							w.WriteHeader(tt.status)
+							_, _ = fmt.Fprint(w, `{}`)
+						},
+					),
+				)
+				defer srv.Close()
+
+				client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{srv.URL}})
+				if err != nil {
+					t.Fatalf("could not create client")
+				}
+
+				p := &Publisher{
+					client:  client,
+					index:   FixedIndex("articles", ""),
+					written: &sync.Map{},
+					log:     zap.NewNop().Sugar(),
+				}
+
+				err = p.Publish(newsReader.Article{ID: "aa", Version: tt.version})
+				if (err != nil) != tt.wantErr {
+					t.Errorf("want error=%v, got %v", tt.wantErr, err)
+				}
+			},
+		)
+	}
+}
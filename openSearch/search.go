@@ -0,0 +1,248 @@
+package openSearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"go.uber.org/zap"
+	"newsReader"
+)
+
+// MaxResultWindow is the maximum of Offset+Size OpenSearch serves without a scroll.
+const MaxResultWindow = 10000
+
+// defaultSize is the number of hits of queries without Size.
+const defaultSize = 10
+
+// Sort orders search hits.
+type Sort int
+
+const (
+	Relevance Sort = iota
+	Newest
+	Oldest
+)
+
+// Query searches articles. Empty fields do not restrict the result, all entities of Tags, Pers, Locs and Orgs
+// must be contained in a hit while a hit must be published by one of Sources.
+type Query struct {
+	Text string
+	// Sources are the hosts of the article urls, e.g. www.tagesschau.de.
+	Sources []string
+	// Date is the article date From and To and the date sorts refer to.
+	Date DateField
+	From time.Time
+	To   time.Time
+	Tags []string
+	Pers []string
+	Locs []string
+	Orgs []string
+	// Originals excludes near-duplicates of other articles.
+	Originals bool
+	Sort      Sort
+	Offset    int
+	Size      int
+}
+
+type Hit struct {
+	Article    newsReader.Article  `json:"article"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+type Result struct {
+	Total int   `json:"total"`
+	Hits  []Hit `json:"hits"`
+}
+
+// Searcher runs queries against the articles of an index or alias.
+type Searcher struct {
+	client *opensearch.Client
+	index  string
+	log    *zap.SugaredLogger
+}
+
+func NewSearcher(cfg Config, index string, l *zap.SugaredLogger) (*Searcher, error) {
+	if len(index) == 0 {
+		return nil, errors.New("index name must not be empty")
+	}
+
+	client, err := cfg.client()
+	if err != nil {
+		return nil, err
+	}
+
+	ping, err := client.Ping()
+	if err != nil {
+		return nil, err
+	}
+	if ping.StatusCode >= 400 {
+		return nil, fmt.Errorf("could not ping opensearch, status code=%v", ping.StatusCode)
+	}
+
+	return &Searcher{client: client, index: index, log: l}, nil
+}
+
+func (s Searcher) Search(q Query) (Result, error) {
+	q = q.withDefaults()
+	err := q.validate()
+	if err != nil {
+		return Result{}, err
+	}
+
+	var res struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Score     float64             `json:"_score"`
+				Source    newsReader.Article  `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	err = s.search(q.body(), &res)
+	if err != nil {
+		return Result{}, err
+	}
+
+	r := Result{Total: res.Hits.Total.Value, Hits: make([]Hit, 0, len(res.Hits.Hits))}
+	for _, h := range res.Hits.Hits {
+		r.Hits = append(r.Hits, Hit{Article: h.Source, Score: h.Score, Highlights: h.Highlight})
+	}
+	return r, nil
+}
+
+// search runs the query body and decodes the response into res.
+func (s Searcher) search(body object, res interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal query, %w", err)
+	}
+	s.log.Debugw("search articles", "method", "search", "index", s.index, "query", string(b))
+
+	req := opensearchapi.SearchRequest{Index: []string{s.index}, Body: bytes.NewReader(b)}
+	resp, err := req.Do(context.Background(), s.client)
+	if err != nil {
+		return fmt.Errorf("could not request search in index=%s, %w", s.index, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("opensearch response status code=%v while searching index=%s", resp.StatusCode, s.index)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return fmt.Errorf("could not decode search response, %w", err)
+	}
+	return nil
+}
+
+// withDefaults returns q with the default size if it has none, so it is validated as it is searched.
+func (q Query) withDefaults() Query {
+	if q.Size == 0 {
+		q.Size = defaultSize
+	}
+	return q
+}
+
+func (q Query) validate() error {
+	if q.Offset < 0 || q.Size < 0 {
+		return fmt.Errorf("offset=%v and size=%v must not be negative", q.Offset, q.Size)
+	}
+	if q.Offset+q.Size > MaxResultWindow {
+		return fmt.Errorf("offset+size must not exceed %v", MaxResultWindow)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("to=%v is before from=%v", q.To, q.From)
+	}
+	return nil
+}
+
+type object map[string]interface{}
+
+func (q Query) body() object {
+	var must []object
+	if len(q.Text) != 0 {
+		must = append(
+			must, object{
+				"multi_match": object{
+					"query":  q.Text,
+					"fields": []string{"title^3", "summary^2", "body"},
+				},
+			},
+		)
+	}
+
+	var filter []object
+	if len(q.Sources) != 0 {
+		var sources []object
+		for _, s := range q.Sources {
+			for _, scheme := range []string{"https", "http"} {
+				sources = append(sources, object{"prefix": object{"url": fmt.Sprintf("%s://%s/", scheme, s)}})
+			}
+		}
+		filter = append(filter, object{"bool": object{"should": sources, "minimum_should_match": 1}})
+	}
+
+	entities := []struct {
+		field  string
+		values []string
+	}{{"tags", q.Tags}, {"pers", q.Pers}, {"locs", q.Locs}, {"orgs", q.Orgs}}
+	for _, e := range entities {
+		for _, v := range e.values {
+			filter = append(filter, object{"term": object{e.field: v}})
+		}
+	}
+
+	if r, ok := dateRange(q.Date, q.From, q.To); ok {
+		filter = append(filter, r)
+	}
+
+	boolQuery := object{}
+	if len(must) != 0 {
+		boolQuery["must"] = must
+	}
+	if len(filter) != 0 {
+		boolQuery["filter"] = filter
+	}
+	if q.Originals {
+		boolQuery["must_not"] = []object{{"exists": object{"field": "duplicateOf"}}}
+	}
+
+	body := object{
+		"query": object{"bool": boolQuery},
+		"from":  q.Offset,
+		"size":  q.Size,
+		"highlight": object{
+			"fields": object{
+				"title":   object{"number_of_fragments": 0},
+				"summary": object{"number_of_fragments": 0},
+				"body":    object{"fragment_size": 150, "number_of_fragments": 3},
+			},
+		},
+	}
+
+	switch q.Sort {
+	case Newest:
+		body["sort"] = []object{{q.Date.field(): object{"order": "desc"}}, {"_score": object{"order": "desc"}}}
+	case Oldest:
+		body["sort"] = []object{{q.Date.field(): object{"order": "asc"}}, {"_score": object{"order": "desc"}}}
+	}
+	return body
+}
+
+func (d DateField) field() string {
+	if d == Created {
+		return "created"
+	}
+	return "collected"
+}
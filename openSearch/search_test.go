@@ -0,0 +1,30 @@
+package openSearch
+
+import (
+	"testing"
+)
+
+func TestQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   Query
+		wantErr bool
+	}{
+		{name: "defaults", query: Query{}, wantErr: false},
+		{name: "last page", query: Query{Offset: MaxResultWindow - 10, Size: 10}, wantErr: false},
+		{name: "beyond window", query: Query{Offset: MaxResultWindow - 5, Size: 10}, wantErr: true},
+		{name: "default size beyond window", query: Query{Offset: MaxResultWindow - 5}, wantErr: true},
+		{name: "negative offset", query: Query{Offset: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := tt.query.withDefaults().validate()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
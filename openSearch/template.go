@@ -0,0 +1,203 @@
+package openSearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// templateVersion must be increased on every change of the article mapping.
+const templateVersion = 6
+
+const templateName = "articles"
+
+var indexPatterns = []string{"article-*", "articles-*"}
+
+type property map[string]interface{}
+
+func text() property {
+	return property{"type": "text", "analyzer": "german"}
+}
+
+func keyword() property {
+	return property{"type": "keyword"}
+}
+
+func date() property {
+	return property{"type": "date", "ignore_malformed": true}
+}
+
+// properties is the explicit mapping of newsReader.Article.
+var properties = map[string]property{
+	"id":            keyword(),
+	"author":        keyword(),
+	"title":         text(),
+	"body":          text(),
+	"summary":       text(),
+	"summaryMethod": keyword(),
+	"created":       date(),
+	"collected":     date(),
+	"url":           keyword(),
+	"tags":          keyword(),
+	"pers":          keyword(),
+	"locs":          keyword(),
+	"orgs":          keyword(),
+	"keywords":      keyword(),
+	"story":         keyword(),
+	"duplicateOf":   keyword(),
+	"similarity":    {"type": "float"},
+	"processingErrors": {
+		"properties": map[string]property{
+			"processor": keyword(),
+			"version":   keyword(),
+			"error":     {"type": "text"},
+		},
+	},
+}
+
+func template() map[string]interface{} {
+	return map[string]interface{}{
+		"index_patterns": indexPatterns,
+		"version":        templateVersion,
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"dynamic":    false,
+				"properties": properties,
+			},
+		},
+	}
+}
+
+// InstallTemplate installs the article index template unless a newer version is installed already.
+func (p Publisher) InstallTemplate() error {
+	installed, err := p.templateVersion()
+	if err != nil {
+		return err
+	}
+	if installed >= templateVersion {
+		p.log.Infow(
+			"index template up to date",
+			"method", "InstallTemplate",
+			"installed", installed,
+			"version", templateVersion,
+		)
+		return nil
+	}
+
+	b, err := json.Marshal(template())
+	if err != nil {
+		return fmt.Errorf("could not marshal index template, %w", err)
+	}
+
+	p.log.Infow("install index template", "method", "InstallTemplate", "name", templateName, "version", templateVersion)
+	req := opensearchapi.IndicesPutIndexTemplateRequest{Name: templateName, Body: bytes.NewReader(b)}
+	resp, err := req.Do(context.Background(), p.client)
+	if err != nil {
+		return fmt.Errorf("could not request put index template=%s, %w", templateName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("opensearch response status code=%v while putting index template=%s", resp.StatusCode, templateName)
+	}
+	return nil
+}
+
+// templateVersion returns the version of the installed article index template, 0 if none is installed.
+func (p Publisher) templateVersion() (int, error) {
+	req := opensearchapi.IndicesGetIndexTemplateRequest{Name: []string{templateName}}
+	resp, err := req.Do(context.Background(), p.client)
+	if err != nil {
+		return 0, fmt.Errorf("could not request index template=%s, %w", templateName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if resp.IsError() {
+		return 0, fmt.Errorf("opensearch response status code=%v while reading index template=%s", resp.StatusCode, templateName)
+	}
+
+	var res struct {
+		IndexTemplates []struct {
+			IndexTemplate struct {
+				Version int `json:"version"`
+			} `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return 0, fmt.Errorf("could not decode index template=%s, %w", templateName, err)
+	}
+	if len(res.IndexTemplates) == 0 {
+		return 0, nil
+	}
+	return res.IndexTemplates[0].IndexTemplate.Version, nil
+}
+
+// CheckMapping returns an error if an index of the publisher exists with a mapping incompatible to the
+// article mapping, e.g. because it has been created by dynamic mapping before the template was installed.
+func (p Publisher) CheckMapping() error {
+	pattern := p.index.pattern()
+	req := opensearchapi.IndicesGetMappingRequest{Index: []string{pattern}}
+	resp, err := req.Do(context.Background(), p.client)
+	if err != nil {
+		return fmt.Errorf("could not request mapping of index=%s, %w", pattern, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.IsError() {
+		return fmt.Errorf("opensearch response status code=%v while reading mapping of index=%s", resp.StatusCode, pattern)
+	}
+
+	var res map[string]struct {
+		Mappings struct {
+			Properties map[string]property `json:"properties"`
+		} `json:"mappings"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("could not decode mapping of index=%s, %w", pattern, err)
+	}
+
+	for idx, m := range res {
+		err = compatible(m.Mappings.Properties, properties)
+		if err != nil {
+			return fmt.Errorf("incompatible mapping of index=%s, rebuild it with cmd/replay, %w", idx, err)
+		}
+	}
+	return nil
+}
+
+// compatible returns an error for the first field in have whose type or analyzer differs from want.
+func compatible(have, want map[string]property) error {
+	fields := make([]string, 0, len(want))
+	for f := range want {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	for _, f := range fields {
+		h, ok := have[f]
+		if !ok {
+			continue
+		}
+		w := want[f]
+		if h["type"] != w["type"] {
+			return fmt.Errorf("field=%s has type=%v, want=%v", f, h["type"], w["type"])
+		}
+		if w["analyzer"] != nil && h["analyzer"] != w["analyzer"] {
+			return fmt.Errorf("field=%s has analyzer=%v, want=%v", f, h["analyzer"], w["analyzer"])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package openSearch
+
+import "testing"
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    map[string]property
+		wantErr bool
+	}{
+		{
+			name:    "empty index",
+			have:    map[string]property{},
+			wantErr: false,
+		},
+		{
+			name:    "same mapping",
+			have:    properties,
+			wantErr: false,
+		},
+		{
+			name: "dynamic date as text",
+			have: map[string]property{
+				"created": {"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing analyzer",
+			have:    map[string]property{"body": {"type": "text"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			have:    map[string]property{"other": {"type": "text"}},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := compatible(tt.have, properties)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("compatible() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
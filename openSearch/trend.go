@@ -0,0 +1,410 @@
+package openSearch
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EntityField is the article field entities are counted in.
+type EntityField string
+
+const (
+	Pers EntityField = "pers"
+	Locs EntityField = "locs"
+	Orgs EntityField = "orgs"
+	Tags EntityField = "tags"
+)
+
+func (f EntityField) validate() error {
+	switch f {
+	case Pers, Locs, Orgs, Tags:
+		return nil
+	default:
+		return fmt.Errorf("unknown entity field=%s", f)
+	}
+}
+
+// Interval is the width of a time bucket.
+type Interval string
+
+const (
+	Hour Interval = "hour"
+	Day  Interval = "day"
+	Week Interval = "week"
+)
+
+// maxEntities limits the number of entities per aggregation.
+const maxEntities = 1000
+
+// Mention counts the articles mentioning an entity.
+type Mention struct {
+	Entity string `json:"entity"`
+	Count  int    `json:"count"`
+}
+
+type Bucket struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// Series counts the articles mentioning an entity per time bucket.
+type Series struct {
+	Entity  string   `json:"entity"`
+	Total   int      `json:"total"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// TrendQuery selects the entities of Field to count in articles dated between From and To.
+// Without Entities the Size most mentioned entities are counted.
+type TrendQuery struct {
+	Field    EntityField
+	Entities []string
+	Date     DateField
+	From     time.Time
+	To       time.Time
+	Interval Interval
+	Size     int
+}
+
+func (q TrendQuery) validate() error {
+	err := q.Field.validate()
+	if err != nil {
+		return err
+	}
+	switch q.Interval {
+	case Hour, Day, Week:
+	default:
+		return fmt.Errorf("unknown interval=%s", q.Interval)
+	}
+	if q.Size < 1 || q.Size > maxEntities {
+		return fmt.Errorf("size=%v must be between 1 and %v", q.Size, maxEntities)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("to=%v is before from=%v", q.To, q.From)
+	}
+	return nil
+}
+
+type termsAgg struct {
+	Buckets []struct {
+		Key      string `json:"key"`
+		DocCount int    `json:"doc_count"`
+		Mentions struct {
+			Buckets []struct {
+				Key      int64 `json:"key"`
+				DocCount int   `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"mentions"`
+	} `json:"buckets"`
+}
+
+func (t termsAgg) mentions() []Mention {
+	mm := make([]Mention, 0, len(t.Buckets))
+	for _, b := range t.Buckets {
+		mm = append(mm, Mention{Entity: b.Key, Count: b.DocCount})
+	}
+	return mm
+}
+
+// Mentions counts the articles mentioning every entity per time bucket, near-duplicates are not counted.
+func (s Searcher) Mentions(q TrendQuery) ([]Series, error) {
+	err := q.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := object{"field": q.Field, "size": q.Size}
+	if len(q.Entities) != 0 {
+		terms["include"] = q.Entities
+	}
+
+	histogram := object{"field": q.Date.field(), "calendar_interval": q.Interval, "min_doc_count": 0}
+	if !q.From.IsZero() && !q.To.IsZero() {
+		histogram["extended_bounds"] = object{"min": q.From.UTC().Format(time.RFC3339), "max": q.To.UTC().Format(time.RFC3339)}
+	}
+
+	body := object{
+		"size":  0,
+		"query": originals(rangeFilter(q.Date, q.From, q.To)...),
+		"aggs": object{
+			"entities": object{
+				"terms": terms,
+				"aggs":  object{"mentions": object{"date_histogram": histogram}},
+			},
+		},
+	}
+
+	var res struct {
+		Aggregations struct {
+			Entities termsAgg `json:"entities"`
+		} `json:"aggregations"`
+	}
+	err = s.search(body, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, 0, len(res.Aggregations.Entities.Buckets))
+	for _, e := range res.Aggregations.Entities.Buckets {
+		sr := Series{Entity: e.Key, Total: e.DocCount, Buckets: make([]Bucket, 0, len(e.Mentions.Buckets))}
+		for _, b := range e.Mentions.Buckets {
+			sr.Buckets = append(sr.Buckets, Bucket{Time: time.UnixMilli(b.Key).UTC(), Count: b.DocCount})
+		}
+		series = append(series, sr)
+	}
+	return series, nil
+}
+
+// RisingQuery compares the mentions of entities between From and To with the mentions in the
+// baseline window between BaselineFrom and BaselineTo.
+type RisingQuery struct {
+	Field        EntityField
+	Date         DateField
+	From         time.Time
+	To           time.Time
+	BaselineFrom time.Time
+	BaselineTo   time.Time
+	// MinCount drops entities mentioned less often in the window.
+	MinCount int
+	Size     int
+}
+
+func (q RisingQuery) validate() error {
+	err := q.Field.validate()
+	if err != nil {
+		return err
+	}
+	if q.Size < 1 || q.Size > maxEntities {
+		return fmt.Errorf("size=%v must be between 1 and %v", q.Size, maxEntities)
+	}
+	if !q.From.Before(q.To) || !q.BaselineFrom.Before(q.BaselineTo) {
+		return fmt.Errorf("window and baseline must be non-empty time ranges")
+	}
+	return nil
+}
+
+// Rising is an entity mentioned more often than expected from its baseline.
+type Rising struct {
+	Entity   string `json:"entity"`
+	Count    int    `json:"count"`
+	Baseline int    `json:"baseline"`
+	// Expected is the baseline count scaled to the length of the window.
+	Expected float64 `json:"expected"`
+	// Score is (Count+1)/(Expected+1), entities without baseline mentions score highest.
+	Score float64 `json:"score"`
+}
+
+// Rising returns the Size entities whose mentions rose most compared to the baseline window.
+func (s Searcher) Rising(q RisingQuery) ([]Rising, error) {
+	err := q.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// candidates are the most mentioned entities of the window, rising entities are ranked among them
+	current, err := s.count(q.Field, nil, q.Date, q.From, q.To, maxEntities)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return []Rising{}, nil
+	}
+
+	entities := make([]string, 0, len(current))
+	for _, m := range current {
+		entities = append(entities, m.Entity)
+	}
+	baseline, err := s.count(q.Field, entities, q.Date, q.BaselineFrom, q.BaselineTo, len(entities))
+	if err != nil {
+		return nil, err
+	}
+
+	ratio := float64(q.To.Sub(q.From)) / float64(q.BaselineTo.Sub(q.BaselineFrom))
+	return rising(current, baseline, ratio, q.MinCount, q.Size), nil
+}
+
+// count returns the number of articles mentioning the Size most mentioned entities of f, restricted to entities if given.
+func (s Searcher) count(f EntityField, entities []string, d DateField, from, to time.Time, size int) ([]Mention, error) {
+	terms := object{"field": f, "size": size}
+	if len(entities) != 0 {
+		terms["include"] = entities
+	}
+
+	body := object{
+		"size":  0,
+		"query": originals(rangeFilter(d, from, to)...),
+		"aggs":  object{"entities": object{"terms": terms}},
+	}
+
+	var res struct {
+		Aggregations struct {
+			Entities termsAgg `json:"entities"`
+		} `json:"aggregations"`
+	}
+	err := s.search(body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Aggregations.Entities.mentions(), nil
+}
+
+// rising scores current against the baseline counts scaled by ratio, the length of the window
+// divided by the length of the baseline, and returns the size highest scores.
+func rising(current, baseline []Mention, ratio float64, minCount, size int) []Rising {
+	base := make(map[string]int, len(baseline))
+	for _, m := range baseline {
+		base[m.Entity] = m.Count
+	}
+
+	rr := make([]Rising, 0, len(current))
+	for _, m := range current {
+		if m.Count < minCount {
+			continue
+		}
+		expected := float64(base[m.Entity]) * ratio
+		rr = append(
+			rr, Rising{
+				Entity:   m.Entity,
+				Count:    m.Count,
+				Baseline: base[m.Entity],
+				Expected: expected,
+				Score:    (float64(m.Count) + 1) / (expected + 1),
+			},
+		)
+	}
+
+	sort.SliceStable(
+		rr, func(i, j int) bool {
+			if rr[i].Score != rr[j].Score {
+				return rr[i].Score > rr[j].Score
+			}
+			return rr[i].Count > rr[j].Count
+		},
+	)
+	if len(rr) > size {
+		rr = rr[:size]
+	}
+	return rr
+}
+
+// CoOccurrenceQuery counts the entities of the With fields mentioned together with Entity of Field.
+type CoOccurrenceQuery struct {
+	Field  EntityField
+	Entity string
+	With   []EntityField
+	Date   DateField
+	From   time.Time
+	To     time.Time
+	Size   int
+}
+
+func (q CoOccurrenceQuery) validate() error {
+	err := q.Field.validate()
+	if err != nil {
+		return err
+	}
+	if len(q.Entity) == 0 {
+		return fmt.Errorf("entity must not be empty")
+	}
+	for _, w := range q.With {
+		err = w.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if q.Size < 1 || q.Size > maxEntities {
+		return fmt.Errorf("size=%v must be between 1 and %v", q.Size, maxEntities)
+	}
+	return nil
+}
+
+type CoOccurrence struct {
+	Entity string `json:"entity"`
+	// Count is the number of articles mentioning Entity.
+	Count int                       `json:"count"`
+	With  map[EntityField][]Mention `json:"with"`
+}
+
+// CoOccurrence counts the articles mentioning the entity together with other entities.
+// Without With fields the entities of all fields are counted.
+func (s Searcher) CoOccurrence(q CoOccurrenceQuery) (CoOccurrence, error) {
+	err := q.validate()
+	if err != nil {
+		return CoOccurrence{}, err
+	}
+	if len(q.With) == 0 {
+		q.With = []EntityField{Pers, Locs, Orgs, Tags}
+	}
+
+	aggs := object{}
+	for _, w := range q.With {
+		terms := object{"field": w, "size": q.Size}
+		if w == q.Field {
+			terms["exclude"] = []string{q.Entity}
+		}
+		aggs[string(w)] = object{"terms": terms}
+	}
+
+	filter := append(rangeFilter(q.Date, q.From, q.To), object{"term": object{string(q.Field): q.Entity}})
+	body := object{
+		"size":             0,
+		"track_total_hits": true,
+		"query":            originals(filter...),
+		"aggs":             aggs,
+	}
+
+	var res struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+		Aggregations map[EntityField]termsAgg `json:"aggregations"`
+	}
+	err = s.search(body, &res)
+	if err != nil {
+		return CoOccurrence{}, err
+	}
+
+	co := CoOccurrence{Entity: q.Entity, Count: res.Hits.Total.Value, With: make(map[EntityField][]Mention)}
+	for _, w := range q.With {
+		co.With[w] = res.Aggregations[w].mentions()
+	}
+	return co, nil
+}
+
+// dateRange filters articles dated between from and to, zero times are open bounds.
+func dateRange(d DateField, from, to time.Time) (object, bool) {
+	if from.IsZero() && to.IsZero() {
+		return nil, false
+	}
+
+	r := object{}
+	if !from.IsZero() {
+		r["gte"] = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		r["lte"] = to.UTC().Format(time.RFC3339)
+	}
+	return object{"range": object{d.field(): r}}, true
+}
+
+// originals matches all articles which are no near-duplicates and pass all filters, so syndicated
+// copies are counted once.
+func originals(filter ...object) object {
+	q := object{"must_not": []object{{"exists": object{"field": "duplicateOf"}}}}
+	if len(filter) != 0 {
+		q["filter"] = filter
+	}
+	return object{"bool": q}
+}
+
+// rangeFilter returns the date range filter, none for open bounds.
+func rangeFilter(d DateField, from, to time.Time) []object {
+	r, ok := dateRange(d, from, to)
+	if !ok {
+		return nil
+	}
+	return []object{r}
+}
@@ -0,0 +1,90 @@
+package openSearch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go"
+	"go.uber.org/zap"
+)
+
+func TestRising(t *testing.T) {
+	current := []Mention{{"Scholz", 20}, {"Merkel", 10}, {"Macron", 3}, {"Biden", 1}}
+	baseline := []Mention{{"Scholz", 70}, {"Merkel", 7}}
+
+	// the window is a day, the baseline a week
+	got := rising(current, baseline, 1.0/7, 2, 2)
+
+	want := []string{"Merkel", "Macron"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v rising entities, got %+v", len(want), got)
+	}
+	for i, r := range got {
+		if r.Entity != want[i] {
+			t.Errorf("want %s at %v, got %+v", want[i], i, r)
+		}
+	}
+	if got[0].Expected != 1 || got[0].Score != 5.5 {
+		t.Errorf("want Merkel expected=1 and score=5.5, got %+v", got[0])
+	}
+}
+
+func TestMentions(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/" {
+					_, _ = fmt.Fprint(w, `{"version": {"number": "1.2.4", "distribution": "opensearch"}}`)
+					return
+				}
+
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				_, _ = fmt.Fprint(
+					w, `{"aggregations": {"entities": {"buckets": [{"key": "Scholz", "doc_count": 3, "mentions": {"buckets": [
+					{"key": 1640995200000, "doc_count": 1}, {"key": 1641081600000, "doc_count": 2}]}}]}}}`,
+				)
+			},
+		),
+	)
+	defer srv.Close()
+
+	client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("could not create client")
+	}
+	s := Searcher{client: client, index: "articles", log: zap.NewNop().Sugar()}
+
+	series, err := s.Mentions(
+		TrendQuery{
+			Field:    Pers,
+			Entities: []string{"Scholz"},
+			From:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2022, 1, 2, 23, 0, 0, 0, time.UTC),
+			Interval: Day,
+			Size:     10,
+		},
+	)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	for _, want := range []string{`"include":["Scholz"]`, `"calendar_interval":"day"`, `"range":{"collected"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want body to contain %s, got %s", want, body)
+		}
+	}
+	if len(series) != 1 || series[0].Total != 3 || len(series[0].Buckets) != 2 {
+		t.Fatalf("want one series with 2 buckets, got %+v", series)
+	}
+	if !series[0].Buckets[1].Time.Equal(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)) || series[0].Buckets[1].Count != 2 {
+		t.Errorf("want 2 mentions on 2022-01-02, got %+v", series[0].Buckets[1])
+	}
+}
@@ -4,28 +4,69 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
+// ConflictPolicy decides how an Operator handles articles whose stream moved on
+// while they were processed.
+type ConflictPolicy int
+
+const (
+	// DropOnConflict drops the article, the transition is left to whoever moved the stream.
+	DropOnConflict ConflictPolicy = iota
+	// RetryOnConflict re-reads the article stream and processes the latest article again
+	// if it still awaits processing.
+	RetryOnConflict
+)
+
+const maxConflictRetries = 3
+
+// FailurePolicy decides how an Operator handles articles a Processor failed on.
+type FailurePolicy int
+
+const (
+	// Optional processors are skipped on failure, the failure is recorded in Article.ProcessingErrors.
+	Optional FailurePolicy = iota
+	// Required processors are retried on failure. Articles they still fail on are not processed further
+	// and published to the dead-letter publisher, or dropped without one.
+	Required
+)
+
 type Operator struct {
-	processors []Processor
+	// stages are the processors grouped by their dependencies, see plan.
+	stages     [][]Processor
+	conditions map[string][]Filter
+	policies   map[string]FailurePolicy
+	retries    int
+	backoff    time.Duration
+	dead       Publisher
 	con        Consumer
 	pub        Publisher
+	rdr        Reader
+	conflict   ConflictPolicy
 	log        *zap.SugaredLogger
 	numWorker  int
 	tasks      chan Article
 }
 
 func NewOperatorBuilder() *OperatorBuilder {
-	return &OperatorBuilder{}
+	return &OperatorBuilder{cc: make(map[string][]Filter), fp: make(map[string]FailurePolicy)}
 }
 
 type OperatorBuilder struct {
 	pp []Processor
+	cc map[string][]Filter
+	fp map[string]FailurePolicy
+	rn int
+	bo time.Duration
+	d  Publisher
 	c  Consumer
 	p  Publisher
+	r  Reader
+	cp ConflictPolicy
 	l  *zap.SugaredLogger
 	n  int
 }
@@ -35,6 +76,32 @@ func (b *OperatorBuilder) Processors(pp ...Processor) *OperatorBuilder {
 	return b
 }
 
+// When restricts the processor with name to articles selected by all filters, e.g. to save model calls
+// on articles which do not need them. Skipped processors keep the fields of the article.
+func (b *OperatorBuilder) When(name string, ff ...Filter) *OperatorBuilder {
+	b.cc[name] = append(b.cc[name], ff...)
+	return b
+}
+
+// OnFailure sets the failure policy of the processor with name, processors are Optional by default.
+func (b *OperatorBuilder) OnFailure(name string, fp FailurePolicy) *OperatorBuilder {
+	b.fp[name] = fp
+	return b
+}
+
+// Retries sets how often a failing Required processor is retried, waiting backoff times the attempt in between.
+func (b *OperatorBuilder) Retries(n int, backoff time.Duration) *OperatorBuilder {
+	b.rn = n
+	b.bo = backoff
+	return b
+}
+
+// DeadLetter sets the publisher of articles a Required processor failed on.
+func (b *OperatorBuilder) DeadLetter(p Publisher) *OperatorBuilder {
+	b.d = p
+	return b
+}
+
 func (b *OperatorBuilder) Consumer(c Consumer) *OperatorBuilder {
 	b.c = c
 	return b
@@ -45,6 +112,16 @@ func (b *OperatorBuilder) Publisher(p Publisher) *OperatorBuilder {
 	return b
 }
 
+func (b *OperatorBuilder) Reader(r Reader) *OperatorBuilder {
+	b.r = r
+	return b
+}
+
+func (b *OperatorBuilder) OnConflict(cp ConflictPolicy) *OperatorBuilder {
+	b.cp = cp
+	return b
+}
+
 func (b *OperatorBuilder) Logger(l *zap.SugaredLogger) *OperatorBuilder {
 	b.l = l
 	return b
@@ -65,26 +142,65 @@ func (b *OperatorBuilder) Build() (*Operator, error) {
 	if b.p == nil {
 		return nil, errors.New("no pub provided")
 	}
+	if b.cp == RetryOnConflict && b.r == nil {
+		return nil, errors.New("no reader provided to retry on conflict")
+	}
 	if b.n < 1 {
 		b.n = 1
 		b.l.Warnw("numWorker < 1, set to 1", "method", "Build")
 	}
-	if len(b.pp) == 0 {
-		b.pp = []Processor{}
+	stages, err := plan(b.pp)
+	if err != nil {
+		return nil, fmt.Errorf("could not plan processors, %w", err)
+	}
+	for name := range b.cc {
+		if !b.provided(name) {
+			return nil, fmt.Errorf("no processor=%s provided for condition", name)
+		}
+	}
+	for name := range b.fp {
+		if !b.provided(name) {
+			return nil, fmt.Errorf("no processor=%s provided for failure policy", name)
+		}
 	}
 
 	return &Operator{
 		log:        b.l,
 		con:        b.c,
 		pub:        b.p,
+		rdr:        b.r,
+		conflict:   b.cp,
 		numWorker:  b.n,
-		processors: b.pp,
+		stages:     stages,
+		conditions: b.cc,
+		policies:   b.fp,
+		retries:    b.rn,
+		backoff:    b.bo,
+		dead:       b.d,
 	}, nil
 }
 
+func (b *OperatorBuilder) provided(name string) bool {
+	for _, p := range b.pp {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run operates until the consumer closes.
 func (opr Operator) Run() error {
+	return opr.RunUntil(nil)
+}
+
+// RunUntil operates until the consumer closes or stop is closed. On stop no more articles are taken from
+// the consumer, the articles taken already are processed and a BatchPublisher is flushed before it returns.
+func (opr Operator) RunUntil(stop <-chan struct{}) error {
 	opr.tasks = make(chan Article, opr.numWorker)
 
+	closePub := watch(opr.pub, opr.log)
+
 	opr.log.Infow("setup worker pool", "method", "Run", "numWorker", strconv.Itoa(opr.numWorker))
 	eg := new(errgroup.Group)
 	for i := 0; i < opr.numWorker; i++ {
@@ -92,38 +208,57 @@ func (opr Operator) Run() error {
 		eg.Go(opr.operate)
 	}
 
-	go opr.con.Consume(opr.tasks)
+	consumed := make(chan Article)
+	go opr.con.Consume(consumed)
+	go opr.forward(consumed, stop)
 
-	return eg.Wait()
+	err := eg.Wait()
+	for _, e := range closePub() {
+		if err == nil {
+			err = errors.New("operate error")
+		}
+		err = fmt.Errorf("%v, %w", e.Error(), err)
+	}
+	return err
+}
+
+// forward passes the consumed articles to the workers until consumed or stop is closed. A consumer which is
+// a Stopper is stopped and the articles it consumed already are passed on.
+func (opr Operator) forward(consumed <-chan Article, stop <-chan struct{}) {
+	defer close(opr.tasks)
+	for {
+		select {
+		case <-stop:
+			opr.log.Infow("stop consuming", "method", "forward")
+			s, ok := opr.con.(Stopper)
+			if !ok {
+				return
+			}
+			s.Stop()
+			stop = nil
+		case a, ok := <-consumed:
+			if !ok {
+				return
+			}
+			opr.tasks <- a
+		}
+	}
 }
 
 func (opr Operator) operate() error {
-	var ee []error
+	var ee errorList
 
 	for a := range opr.tasks {
 		opr.log.Debugw("received article", "method", "operate", "articleID", a.ID)
 
-		a, errs := opr.preprocess(a)
-		if len(errs) != 0 {
-			ee = append(ee, errs...)
-		}
-
-		err := opr.pub.Publish(a)
-		if err != nil {
-			opr.log.Warnw(
-				"publish error",
-				"method", "operate",
-				"articleID", a.ID,
-				"errMsg", err.Error(),
-			)
-			ee = append(ee, fmt.Errorf("publish article with ID=%v failed, %w", a.ID, err))
-			continue
+		for _, err := range opr.handle(a, 0) {
+			ee.add(err)
 		}
 	}
 
-	if len(ee) != 0 {
+	if errs := ee.errors(); len(errs) != 0 {
 		retError := errors.New("operate error")
-		for _, e := range ee {
+		for _, e := range errs {
 			retError = fmt.Errorf("%v, %w", e.Error(), retError)
 		}
 		return retError
@@ -131,28 +266,181 @@ func (opr Operator) operate() error {
 	return nil
 }
 
-func (opr Operator) preprocess(a Article) (Article, []error) {
+func (opr Operator) handle(a Article, attempt int) []error {
+	a, err := opr.preprocess(a)
+
+	pub := opr.pub
+	if err != nil {
+		if opr.dead == nil {
+			opr.log.Warnw("drop failed article", "method", "handle", "articleID", a.ID, "errMsg", err.Error())
+			return []error{err}
+		}
+		opr.log.Warnw("dead-letter failed article", "method", "handle", "articleID", a.ID, "errMsg", err.Error())
+		pub = opr.dead
+	}
+
+	err = pub.Publish(a)
+	var wev *WrongExpectedVersionError
+	if errors.As(err, &wev) {
+		return opr.resolve(a, attempt)
+	}
+	if err != nil {
+		opr.log.Warnw(
+			"publish error",
+			"method", "handle",
+			"articleID", a.ID,
+			"errMsg", err.Error(),
+		)
+		return []error{fmt.Errorf("publish article with ID=%v failed, %w", a.ID, err)}
+	}
+
+	return nil
+}
+
+func (opr Operator) resolve(a Article, attempt int) []error {
+	if opr.conflict == DropOnConflict || attempt >= maxConflictRetries {
+		opr.log.Infow("drop conflicting article", "method", "resolve", "articleID", a.ID, "attempt", attempt)
+		return nil
+	}
+
+	latest, pending, err := opr.rdr.Latest(a.ID)
+	if err != nil {
+		return []error{fmt.Errorf("could not re-read article with ID=%v, %w", a.ID, err)}
+	}
+	if !pending {
+		opr.log.Infow("article already moved on, drop", "method", "resolve", "articleID", a.ID)
+		return nil
+	}
+
+	opr.log.Infow("retry conflicting article", "method", "resolve", "articleID", a.ID, "attempt", attempt+1)
+	return opr.handle(latest, attempt+1)
+}
+
+// preprocess applies all processors to a. Failures of Optional processors are recorded in a, the failure
+// of a Required processor stops processing and is returned.
+func (opr Operator) preprocess(a Article) (Article, error) {
 	opr.log.Debugw("preprocess article", "method", "preprocess", "articleID", a.ID)
 
-	var ee []error
-	for _, p := range opr.processors {
+	var failed error
+	for _, stage := range opr.stages {
+		stage = opr.selected(stage, a)
+		a = forget(a, stage)
+		merged := a
+		for i, res := range run(stage, a) {
+			p := stage[i]
+			required := opr.policies[p.Name()] == Required
+			if res.err != nil && required {
+				res = opr.retry(p, a, res)
+			}
+			if res.err != nil {
+				opr.log.Warnw(
+					"process error",
+					"method", "operate",
+					"articleID", a.ID,
+					"processorName", p.Name(),
+					"required", required,
+					"errMsg", res.err.Error(),
+				)
 
-		tmp, err := p.Process(a)
-		if err != nil {
-			opr.log.Warnw(
-				"process error",
-				"method", "operate",
-				"articleID", a.ID,
-				"processorName", p.Name(),
-				"errMsg", err.Error(),
-			)
+				merged.ProcessingErrors = append(
+					merged.ProcessingErrors,
+					ProcessingError{Processor: p.Name(), Version: res.version, Error: res.err.Error()},
+				)
+				if required {
+					failed = fmt.Errorf("required processor=%s on article with id=%s failed, %w", p.Name(), a.ID, res.err)
+				}
+				continue
+			}
+
+			merged = merge(merged, res.a, p)
+			merged.Meta.Processors[p.Name()] = res.version
+			merged.Meta.Inputs[p.Name()] = digest(a, reads(p))
+		}
+		a = merged
+
+		if failed != nil {
+			return a, failed
+		}
+	}
 
-			ee = append(ee, fmt.Errorf("processor=%s on article with id=%s failed, %w", p.Name(), a.ID, err))
+	return a, nil
+}
+
+// forget drops the versions, inputs and errors of the processors in stage from a. Those of other
+// processors are kept, e.g. of processors skipped by their conditions or not selected for reprocessing.
+func forget(a Article, stage []Processor) Article {
+	own := make(map[string]bool)
+	for _, p := range stage {
+		own[p.Name()] = true
+	}
+
+	a.Meta.Processors = without(a.Meta.Processors, own)
+	a.Meta.Inputs = without(a.Meta.Inputs, own)
+
+	var errs []ProcessingError
+	for _, pe := range a.ProcessingErrors {
+		if !own[pe.Processor] {
+			errs = append(errs, pe)
+		}
+	}
+	a.ProcessingErrors = errs
+	return a
+}
+
+// without returns a copy of m without the processors in own.
+func without(m map[string]string, own map[string]bool) map[string]string {
+	res := make(map[string]string)
+	for name, v := range m {
+		if !own[name] {
+			res[name] = v
+		}
+	}
+	return res
+}
+
+// retry processes a with the Required processor p until it succeeds or the retries are exhausted.
+func (opr Operator) retry(p Processor, a Article, res result) result {
+	for attempt := 1; attempt <= opr.retries && res.err != nil; attempt++ {
+		opr.log.Infow(
+			"retry processor",
+			"method", "retry",
+			"articleID", a.ID,
+			"processorName", p.Name(),
+			"attempt", attempt,
+			"errMsg", res.err.Error(),
+		)
+		time.Sleep(opr.backoff * time.Duration(attempt))
+
+		res = process(p, a)
+	}
+	return res
+}
+
+// selected returns the processors of stage whose conditions select a.
+func (opr Operator) selected(stage []Processor, a Article) []Processor {
+	var pp []Processor
+	for _, p := range stage {
+		if opr.selects(p, a) {
+			pp = append(pp, p)
 			continue
 		}
+		opr.log.Debugw("skip processor", "method", "selected", "articleID", a.ID, "processorName", p.Name())
+	}
+	return pp
+}
 
-		a = tmp
+func (opr Operator) selects(p Processor, a Article) bool {
+	for _, f := range opr.conditions[p.Name()] {
+		if !f(a) {
+			return false
+		}
 	}
+	return true
+}
 
-	return a, ee
+func version(p Processor) string {
+	if v, ok := p.(Versioned); ok {
+		return v.Version()
+	}
+	return ""
 }
@@ -0,0 +1,17 @@
+package newsReader
+
+// Page is the last fetched version of a web page with its cache validators. Body is empty if only the
+// validators are cached.
+type Page struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	ContentType  string `json:"contentType,omitempty"`
+	Body         []byte `json:"body,omitempty"`
+}
+
+// PageCache persists the last fetched Page by url, so crawlers can send conditional requests. Load returns
+// ErrNotFound for unknown urls.
+type PageCache interface {
+	Load(url string) (Page, error)
+	Save(url string, p Page) error
+}
@@ -0,0 +1,129 @@
+package newsReader
+
+import (
+	"fmt"
+	"sync"
+)
+
+// plan orders pp into stages of processors which can run in parallel. A processor depends on an earlier
+// processor if it reads a field the earlier one writes or writes a field the earlier one reads, so the
+// stages produce the same article as running pp in order. Processors writing the same field without
+// depending on each other conflict, because the order of their writes would be arbitrary.
+func plan(pp []Processor) ([][]Processor, error) {
+	levels := make([]int, len(pp))
+	ancestors := make([]map[int]bool, len(pp))
+
+	var stages [][]Processor
+	for j, p := range pp {
+		ancestors[j] = make(map[int]bool)
+		for i := 0; i < j; i++ {
+			if !overlap(writes(pp[i]), reads(p)) && !overlap(reads(pp[i]), writes(p)) {
+				continue
+			}
+			ancestors[j][i] = true
+			for a := range ancestors[i] {
+				ancestors[j][a] = true
+			}
+			if levels[i]+1 > levels[j] {
+				levels[j] = levels[i] + 1
+			}
+		}
+
+		for i := 0; i < j; i++ {
+			if ancestors[j][i] {
+				continue
+			}
+			for _, f := range writes(p) {
+				if has(writes(pp[i]), f) {
+					return nil, fmt.Errorf("processors=%s and %s write field=%s independently", pp[i].Name(), p.Name(), f)
+				}
+			}
+		}
+
+		if levels[j] == len(stages) {
+			stages = append(stages, nil)
+		}
+		stages[levels[j]] = append(stages[levels[j]], p)
+	}
+	return stages, nil
+}
+
+func overlap(a, b []Field) bool {
+	for _, f := range a {
+		if has(b, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func has(ff []Field, f Field) bool {
+	for _, e := range ff {
+		if e == f {
+			return true
+		}
+	}
+	return false
+}
+
+// union returns a with the fields of b missing in a appended.
+func union(a, b []Field) []Field {
+	for _, f := range b {
+		if !has(a, f) {
+			a = append(a, f)
+		}
+	}
+	return a
+}
+
+type result struct {
+	a   Article
+	err error
+	// version is the version of the processor which produced a.
+	version string
+}
+
+// run applies the processors of stage to a in parallel and returns their results in order.
+func run(stage []Processor, a Article) []result {
+	if len(stage) == 1 {
+		return []result{process(stage[0], a)}
+	}
+
+	rr := make([]result, len(stage))
+	var wg sync.WaitGroup
+	for i, p := range stage {
+		wg.Add(1)
+		go func(i int, p Processor) {
+			defer wg.Done()
+			rr[i] = process(p, a)
+		}(i, p)
+	}
+	wg.Wait()
+	return rr
+}
+
+// process applies p to a, see Delegating for the version of the result.
+func process(p Processor, a Article) result {
+	d, ok := p.(Delegating)
+	if !ok {
+		res, err := p.Process(a)
+		return result{a: res, err: err, version: version(p)}
+	}
+
+	res, used, err := d.Delegate(a)
+	if used == nil {
+		used = p
+	}
+	return result{a: res, err: err, version: version(used)}
+}
+
+// merge returns a with the fields written by p taken from res, res itself for processors which are not Dependent.
+func merge(a, res Article, p Processor) Article {
+	if _, ok := p.(Dependent); !ok {
+		return res
+	}
+	for _, f := range writes(p) {
+		copyField(&a, res, f)
+	}
+	return a
+}
@@ -4,3 +4,25 @@ type Processor interface {
 	Name() string
 	Process(a Article) (Article, error)
 }
+
+// Dependent is implemented by Processors declaring the Article fields they read and write, so an
+// Operator can run independent Processors in parallel. Processors which are not Dependent read and
+// write all fields.
+type Dependent interface {
+	Reads() []Field
+	Writes() []Field
+}
+
+func reads(p Processor) []Field {
+	if d, ok := p.(Dependent); ok {
+		return d.Reads()
+	}
+	return allFields
+}
+
+func writes(p Processor) []Field {
+	if d, ok := p.(Dependent); ok {
+		return d.Writes()
+	}
+	return allFields
+}
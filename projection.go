@@ -0,0 +1,248 @@
+package newsReader
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Event is an article as it has been read from the event of type Type.
+type Event struct {
+	Type    string
+	Article Article
+}
+
+// View is the current state of an article folded from its events and their history ordered by version.
+type View struct {
+	Current Article
+	History []Event
+}
+
+// ProjectionStore persists the views of a Projection. Load returns ErrNotFound for unknown IDs.
+type ProjectionStore interface {
+	Load(id string) (View, error)
+	Save(v View) error
+}
+
+type Projection struct {
+	consumers map[string]Consumer
+	store     ProjectionStore
+	log       *zap.SugaredLogger
+}
+
+func NewProjectionBuilder() *ProjectionBuilder {
+	return &ProjectionBuilder{cc: make(map[string]Consumer)}
+}
+
+type ProjectionBuilder struct {
+	cc map[string]Consumer
+	s  ProjectionStore
+	l  *zap.SugaredLogger
+}
+
+// Consumer adds a consumer of events of type eType.
+func (b *ProjectionBuilder) Consumer(eType string, c Consumer) *ProjectionBuilder {
+	b.cc[eType] = c
+	return b
+}
+
+func (b *ProjectionBuilder) Store(s ProjectionStore) *ProjectionBuilder {
+	b.s = s
+	return b
+}
+
+func (b *ProjectionBuilder) Logger(l *zap.SugaredLogger) *ProjectionBuilder {
+	b.l = l
+	return b
+}
+
+func (b *ProjectionBuilder) Build() (*Projection, error) {
+	if b.l == nil {
+		return nil, errors.New("no logger provided")
+	}
+	if b.s == nil {
+		return nil, errors.New("no store provided")
+	}
+	if len(b.cc) == 0 {
+		return nil, errors.New("no consumer provided")
+	}
+
+	return &Projection{
+		consumers: b.cc,
+		store:     b.s,
+		log:       b.l,
+	}, nil
+}
+
+// Run folds all consumed events into the store until all consumers are closed. Consumers which are
+// a Source replay all past events first and then consume from where the replay ended, e.g. an
+// eventStore.Consumer, so the store holds the views of the whole history.
+func (pr Projection) Run() error {
+	events := make(chan Event)
+
+	var mu sync.Mutex
+	var ee []error
+
+	var wg sync.WaitGroup
+	for eType, con := range pr.consumers {
+		wg.Add(1)
+		go func(eType string, con Consumer) {
+			defer wg.Done()
+
+			err := pr.replay(eType, con, events)
+			if err != nil {
+				pr.log.Errorw("could not replay, do not consume", "method", "Run", "eventType", eType, "errMsg", err)
+				mu.Lock()
+				ee = append(ee, err)
+				mu.Unlock()
+				return
+			}
+
+			pr.log.Infow("start consuming", "method", "Run", "eventType", eType)
+			articles := make(chan Article)
+			go con.Consume(articles)
+			for a := range articles {
+				events <- Event{Type: eType, Article: a}
+			}
+		}(eType, con)
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	for e := range events {
+		err := pr.apply(e)
+		if err != nil {
+			pr.log.Warnw("apply error", "method", "Run", "articleID", e.Article.ID, "errMsg", err.Error())
+			mu.Lock()
+			ee = append(ee, err)
+			mu.Unlock()
+		}
+	}
+
+	if len(ee) != 0 {
+		return fmt.Errorf("%v events could not be projected, first error: %w", len(ee), ee[0])
+	}
+	return nil
+}
+
+// replay passes all past events of con to events if con is a Source.
+func (pr Projection) replay(eType string, con Consumer, events chan<- Event) error {
+	src, ok := con.(Source)
+	if !ok {
+		return nil
+	}
+
+	pr.log.Infow("replay", "method", "replay", "eventType", eType)
+	articles := make(chan Article)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- src.Replay(articles)
+	}()
+
+	n := 0
+	for a := range articles {
+		events <- Event{Type: eType, Article: a}
+		n++
+	}
+
+	err := <-errC
+	if err != nil {
+		return fmt.Errorf("could not replay eventType=%s, %w", eType, err)
+	}
+	pr.log.Infow("replayed", "method", "replay", "eventType", eType, "numEvents", n)
+	return nil
+}
+
+// Article returns the current state of the article with id.
+func (pr Projection) Article(id string) (Article, error) {
+	v, err := pr.store.Load(id)
+	if err != nil {
+		return Article{}, err
+	}
+	return v.Current, nil
+}
+
+// Versions returns all events of the article with id ordered by version.
+func (pr Projection) Versions(id string) ([]Event, error) {
+	v, err := pr.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	return v.History, nil
+}
+
+func (pr Projection) apply(e Event) error {
+	id := e.Article.ID
+	if len(id) == 0 {
+		return errors.New("event without article id")
+	}
+	pr.log.Debugw("apply event", "method", "apply", "articleID", id, "eventType", e.Type, "version", e.Article.Version)
+
+	v, err := pr.store.Load(id)
+	if errors.Is(err, ErrNotFound) {
+		v = View{}
+	} else if err != nil {
+		return fmt.Errorf("could not load view of article with id=%s, %w", id, err)
+	}
+
+	v, ok := fold(v, e)
+	if !ok {
+		pr.log.Debugw("skip known event", "method", "apply", "articleID", id, "version", e.Article.Version)
+		return nil
+	}
+
+	err = pr.store.Save(v)
+	if err != nil {
+		return fmt.Errorf("could not save view of article with id=%s, %w", id, err)
+	}
+	return nil
+}
+
+// fold inserts e into the history of v ordered by version and reports whether v changed.
+// The current state is refolded from the whole history, so events consumed out of order end up
+// in the same state as events consumed in order.
+func fold(v View, e Event) (View, bool) {
+	for _, h := range v.History {
+		if e.Article.Version != 0 && h.Article.Version == e.Article.Version {
+			return v, false
+		}
+	}
+
+	v.History = append(v.History, e)
+	sort.SliceStable(
+		v.History, func(i, j int) bool {
+			return v.History[i].Article.Version < v.History[j].Article.Version
+		},
+	)
+
+	v.Current = Article{}
+	for _, h := range v.History {
+		v.Current = next(v.Current, h)
+	}
+
+	return v, true
+}
+
+// next returns the state following cur after e. A collected event with an unchanged body keeps
+// the enrichments of cur.
+func next(cur Article, e Event) Article {
+	a := e.Article
+	if e.Type == "collected" && a.Body == cur.Body {
+		a.Summary = cur.Summary
+		a.SummaryMethod = cur.SummaryMethod
+		a.Pers = cur.Pers
+		a.Locs = cur.Locs
+		a.Orgs = cur.Orgs
+		a.Keywords = cur.Keywords
+		a.Story = cur.Story
+		a.DuplicateOf = cur.DuplicateOf
+		a.Similarity = cur.Similarity
+	}
+	return a
+}
@@ -1,8 +1,12 @@
 package newsReader
 
+import "fmt"
+
 type Queue interface {
 	Publish(a Article, eType string) error
 	Consume(eType string, c chan<- Article)
+	Latest(id string) (Article, string, error)
+	Replay(eType string, c chan<- Article) error
 }
 
 type Publisher interface {
@@ -12,3 +16,30 @@ type Publisher interface {
 type Consumer interface {
 	Consume(c chan<- Article)
 }
+
+// Stopper is implemented by Consumers which can stop consuming, Consume closes its channel once stopped.
+type Stopper interface {
+	Stop()
+}
+
+// Source replays all past articles into c and closes c when done.
+type Source interface {
+	Replay(c chan<- Article) error
+}
+
+// Reader reads the latest state of a single article stream. Pending reports whether
+// the latest event still awaits the state transition of the reading Operator.
+type Reader interface {
+	Latest(id string) (a Article, pending bool, err error)
+}
+
+// WrongExpectedVersionError is returned on publish if the article stream moved on
+// since the article has been read.
+type WrongExpectedVersionError struct {
+	StreamID string
+	Expected uint64
+}
+
+func (e *WrongExpectedVersionError) Error() string {
+	return fmt.Sprintf("wrong expected version=%v for streamID=%s", e.Expected, e.StreamID)
+}
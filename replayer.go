@@ -0,0 +1,198 @@
+package newsReader
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Replayer struct {
+	src      Source
+	pub      Publisher
+	log      *zap.SugaredLogger
+	interval time.Duration
+	progress int
+	latest   bool
+}
+
+func NewReplayerBuilder() *ReplayerBuilder {
+	return &ReplayerBuilder{}
+}
+
+type ReplayerBuilder struct {
+	s      Source
+	p      Publisher
+	l      *zap.SugaredLogger
+	rate   int
+	prog   int
+	latest bool
+}
+
+func (b *ReplayerBuilder) Source(s Source) *ReplayerBuilder {
+	b.s = s
+	return b
+}
+
+func (b *ReplayerBuilder) Publisher(p Publisher) *ReplayerBuilder {
+	b.p = p
+	return b
+}
+
+func (b *ReplayerBuilder) Logger(l *zap.SugaredLogger) *ReplayerBuilder {
+	b.l = l
+	return b
+}
+
+// Rate limits the number of published articles per second, values < 1 disable the limit.
+func (b *ReplayerBuilder) Rate(n int) *ReplayerBuilder {
+	b.rate = n
+	return b
+}
+
+// Progress logs the progress every n published articles.
+func (b *ReplayerBuilder) Progress(n int) *ReplayerBuilder {
+	b.prog = n
+	return b
+}
+
+// LatestOnly publishes only the latest article of every article stream.
+func (b *ReplayerBuilder) LatestOnly(latest bool) *ReplayerBuilder {
+	b.latest = latest
+	return b
+}
+
+func (b *ReplayerBuilder) Build() (*Replayer, error) {
+	if b.l == nil {
+		return nil, errors.New("no logger provided")
+	}
+	if b.s == nil {
+		return nil, errors.New("no source provided")
+	}
+	if b.p == nil {
+		return nil, errors.New("no publisher provided")
+	}
+	if b.prog < 1 {
+		b.prog = 100
+	}
+
+	var interval time.Duration
+	if b.rate > 0 {
+		interval = time.Second / time.Duration(b.rate)
+	}
+
+	return &Replayer{
+		src:      b.s,
+		pub:      b.p,
+		log:      b.l,
+		interval: interval,
+		progress: b.prog,
+		latest:   b.latest,
+	}, nil
+}
+
+// Run replays all articles of the source and returns the number of published articles.
+func (r Replayer) Run() (int, error) {
+	articles := make(chan Article, r.progress)
+	replayErr := make(chan error, 1)
+
+	closePub := watch(r.pub, r.log)
+
+	r.log.Infow("start replay", "method", "Run", "latestOnly", r.latest, "interval", r.interval.String())
+	go func() {
+		replayErr <- r.src.Replay(articles)
+	}()
+
+	var in <-chan Article = articles
+	if r.latest {
+		in = latestOnly(articles)
+	}
+
+	var tick <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var ee []error
+	published := 0
+	for a := range in {
+		if tick != nil {
+			<-tick
+		}
+
+		err := r.pub.Publish(a)
+		if err != nil {
+			r.log.Warnw("publish error", "method", "Run", "articleID", a.ID, "errMsg", err.Error())
+			ee = append(ee, fmt.Errorf("publish article with ID=%v failed, %w", a.ID, err))
+			continue
+		}
+
+		published++
+		if published%r.progress == 0 {
+			r.log.Infow("replay progress", "method", "Run", "published", published, "failed", len(ee))
+		}
+	}
+
+	failed := closePub()
+	published -= len(failed)
+	ee = append(ee, failed...)
+
+	r.log.Infow("finished replay", "method", "Run", "published", published, "failed", len(ee))
+
+	err := <-replayErr
+	if err != nil {
+		return published, fmt.Errorf("could not replay source, %w", err)
+	}
+	if len(ee) != 0 {
+		return published, fmt.Errorf("%v articles failed to publish, first error: %w", len(ee), ee[0])
+	}
+	return published, nil
+}
+
+// latestOnly drains c and emits the latest article per ID in order of their first appearance, if it passes
+// all filters. Only the articles passing the filters so far are kept while draining c.
+func latestOnly(c <-chan Article, ff ...Filter) <-chan Article {
+	out := make(chan Article)
+
+	go func() {
+		defer close(out)
+
+		var ids []string
+		latest := make(map[string]Article)
+		for a := range c {
+			if !matches(a, ff) {
+				// a newer article of the stream replaces the earlier one
+				delete(latest, a.ID)
+				continue
+			}
+			if _, ok := latest[a.ID]; !ok {
+				ids = append(ids, a.ID)
+			}
+			latest[a.ID] = a
+		}
+
+		for _, id := range ids {
+			a, ok := latest[id]
+			if !ok {
+				continue
+			}
+			// ids of streams which passed the filters again are listed twice
+			delete(latest, id)
+			out <- a
+		}
+	}()
+
+	return out
+}
+
+func matches(a Article, ff []Filter) bool {
+	for _, f := range ff {
+		if !f(a) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package newsReader
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// Filter selects articles, e.g. for reprocessing or for a Processor of an Operator.
+type Filter func(a Article) bool
+
+// Between selects articles collected in [from, to), a zero time leaves the bound open. Articles without
+// a valid collected time are selected by the time their event has been recorded.
+func Between(from, to time.Time) Filter {
+	return func(a Article) bool {
+		t := collectedAt(a)
+		if !from.IsZero() && t.Before(from) {
+			return false
+		}
+		if !to.IsZero() && !t.Before(to) {
+			return false
+		}
+		return true
+	}
+}
+
+// collectedAt returns the time a has been collected, falling back to the time its event has been recorded.
+func collectedAt(a Article) time.Time {
+	t, err := time.Parse(time.RFC3339, a.Collected)
+	if err != nil {
+		return a.Meta.Recorded
+	}
+	return t
+}
+
+// FromHost selects articles crawled from any of hosts.
+func FromHost(hosts ...string) Filter {
+	return func(a Article) bool {
+		u, err := url.Parse(a.Url)
+		if err != nil {
+			return false
+		}
+		return contains(hosts, u.Host)
+	}
+}
+
+// ReplayConsumer is a Consumer replaying the latest past article of every article stream
+// which passes all filters, so an Operator can reprocess them without re-crawling.
+type ReplayConsumer struct {
+	src     Source
+	state   Reader
+	filters []Filter
+	err     error
+	log     *zap.SugaredLogger
+}
+
+// NewReplayConsumer replays the articles of src. If state is not nil, a replayed article is replaced by the
+// latest state of its stream if that is pending for state, e.g. the preprocessed article, so the enrichments
+// of processors not applied again are kept.
+func NewReplayConsumer(src Source, state Reader, l *zap.SugaredLogger, ff ...Filter) *ReplayConsumer {
+	return &ReplayConsumer{src: src, state: state, filters: ff, log: l}
+}
+
+func (rc *ReplayConsumer) Consume(c chan<- Article) {
+	defer close(c)
+	rc.err = nil
+
+	run, err := uuid.NewV4()
+	if err != nil {
+		rc.log.Errorw("could not create run id", "method", "Consume", "errMsg", err)
+		rc.err = fmt.Errorf("could not create run id, %w", err)
+		return
+	}
+
+	articles := make(chan Article)
+	replayErr := make(chan error, 1)
+	go func() {
+		replayErr <- rc.src.Replay(articles)
+	}()
+
+	var n, skipped, failed int
+	var stateErr error
+	// skipped is written by latestOnly until it closes its channel
+	match := func(a Article) bool {
+		if !rc.match(a) {
+			skipped++
+			return false
+		}
+		return true
+	}
+	for a := range latestOnly(articles, match) {
+		a, err = rc.latest(a)
+		if err != nil {
+			rc.log.Errorw("could not read latest state", "method", "Consume", "articleID", a.ID, "errMsg", err)
+			failed++
+			stateErr = err
+			continue
+		}
+
+		// reprocessed articles are appended to streams which moved on since
+		a.Version = 0
+		a.Meta.CorrelationID = run.String()
+
+		n++
+		c <- a
+	}
+
+	err = <-replayErr
+	switch {
+	case err != nil:
+		rc.log.Errorw("could not replay source", "method", "Consume", "errMsg", err)
+		rc.err = fmt.Errorf("could not replay source, %w", err)
+	case stateErr != nil:
+		rc.err = fmt.Errorf("could not read latest state of %d articles, %w", failed, stateErr)
+	}
+
+	rc.log.Infow(
+		"finished replay",
+		"method", "Consume",
+		"run", run.String(),
+		"numArticles", n,
+		"skipped", skipped,
+		"failed", failed,
+	)
+}
+
+// Err returns the error of the last Consume, it is set once Consume closed its channel.
+func (rc *ReplayConsumer) Err() error {
+	return rc.err
+}
+
+// latest returns the latest state of the stream of a if it is pending for the state Reader, a otherwise.
+func (rc *ReplayConsumer) latest(a Article) (Article, error) {
+	if rc.state == nil {
+		return a, nil
+	}
+
+	latest, pending, err := rc.state.Latest(a.ID)
+	if err != nil {
+		return a, fmt.Errorf("could not read article with ID=%v, %w", a.ID, err)
+	}
+	if !pending {
+		return a, nil
+	}
+	return latest, nil
+}
+
+func (rc *ReplayConsumer) match(a Article) bool {
+	return matches(a, rc.filters)
+}
@@ -0,0 +1,266 @@
+package newsReader
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing returns the time of the next run after t.
+type Timing interface {
+	Next(t time.Time) time.Time
+}
+
+type every time.Duration
+
+// Every runs every d.
+func Every(d time.Duration) Timing {
+	return every(d)
+}
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cron is a parsed cron expression, every field is the set of matching values.
+type cron struct {
+	minute, hour, dom, month, dow map[int]bool
+	// anyDay is set if day of month or day of week is *, days then have to match both fields
+	anyDay bool
+}
+
+// maxCronYears bounds the search of the next run of expressions which never match, e.g. "0 0 30 2 *".
+const maxCronYears = 5
+
+// Cron parses a cron expression of the fields minute, hour, day of month, month and day of week. Fields are
+// *, values, ranges a-b, steps */n or a-b/n and lists of them. Like in cron a day matches if the day of
+// month or the day of week matches, unless one of them is *.
+func Cron(expr string) (Timing, error) {
+	ff := strings.Fields(expr)
+	if len(ff) != 5 {
+		return nil, fmt.Errorf("cron expression=%s must have 5 fields", expr)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, f := range ff {
+		set, err := cronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse cron expression=%s, %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// sunday is 0 or 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return cron{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDay: ff[2] == "*" || ff[4] == "*",
+	}, nil
+}
+
+func cronField(f string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i != -1 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step in field=%s", f)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		from, to := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			from, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in field=%s", f)
+			}
+			to = from
+			if len(bounds) == 2 {
+				to, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range in field=%s", f)
+				}
+			} else if step > 1 {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, fmt.Errorf("field=%s out of range %v-%v", f, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (c cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(maxCronYears, 0, 0)
+
+	for t.Before(end) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.day(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c cron) day(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	if c.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// ParseTiming parses a duration like "2h" as Every and everything else as Cron expression.
+func ParseTiming(s string) (Timing, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("interval=%s must be positive", s)
+		}
+		return Every(d), nil
+	}
+	return Cron(s)
+}
+
+// Schedule decides when a Crawler runs.
+type Schedule struct {
+	Timing Timing
+	// Jitter delays every run by a random duration up to Jitter, so crawlers do not hit their sites at
+	// the same time.
+	Jitter time.Duration
+	// ActiveFrom and ActiveTo restrict runs to the hours of day in [ActiveFrom, ActiveTo), wrapping
+	// around midnight if ActiveTo is before ActiveFrom. Equal hours do not restrict runs.
+	ActiveFrom int
+	ActiveTo   int
+	// Location of the timing and active hours, time.Local if nil.
+	Location *time.Location
+}
+
+// ParseHours parses active hours like "6-22" into from and to.
+func ParseHours(s string) (int, int, error) {
+	bounds := strings.SplitN(s, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("hours=%s must be a range from-to", s)
+	}
+	from, err := strconv.Atoi(bounds[0])
+	if err != nil || from < 0 || from > 23 {
+		return 0, 0, fmt.Errorf("invalid start of hours=%s", s)
+	}
+	to, err := strconv.Atoi(bounds[1])
+	if err != nil || to < 0 || to > 24 {
+		return 0, 0, fmt.Errorf("invalid end of hours=%s", s)
+	}
+	return from, to % 24, nil
+}
+
+// Next returns the time of the next run after t, the zero time if the timing never runs again.
+func (s Schedule) Next(t time.Time) time.Time {
+	return s.jitter(s.due(t))
+}
+
+// due returns the time of the next run after t without jitter, the zero time if the timing never runs again.
+func (s Schedule) due(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	n := s.Timing.Next(t.In(loc))
+	if n.IsZero() {
+		return n
+	}
+	if !s.active(n) {
+		n = s.nextActive(n)
+	}
+	return n
+}
+
+// jitter delays the run at n by a random duration up to Jitter.
+func (s Schedule) jitter(n time.Time) time.Time {
+	if n.IsZero() || s.Jitter <= 0 {
+		return n
+	}
+	return n.Add(time.Duration(rand.Int63n(int64(s.Jitter))))
+}
+
+// following returns the due time of the run after the run due at prev, the zero time for the first run.
+// Runs are due relative to the previous due time, so intervals do not drift by the duration of the crawls.
+// Runs missed while crawling are skipped.
+func (s Schedule) following(prev, now time.Time) time.Time {
+	if prev.IsZero() {
+		return s.due(now)
+	}
+	n := s.due(prev)
+	if !n.IsZero() && n.Before(now) {
+		return s.due(now)
+	}
+	return n
+}
+
+func (s Schedule) active(t time.Time) bool {
+	if s.ActiveFrom == s.ActiveTo {
+		return true
+	}
+	h := t.Hour()
+	if s.ActiveFrom < s.ActiveTo {
+		return h >= s.ActiveFrom && h < s.ActiveTo
+	}
+	return h >= s.ActiveFrom || h < s.ActiveTo
+}
+
+// maxInactiveRuns bounds the search of the next active run of timings which never run within the active hours,
+// e.g. "0 3 * * *" with active hours 6-22.
+const maxInactiveRuns = 366 * maxCronYears
+
+// nextActive returns the next run within the active hours after the inactive run n, the zero time if there is
+// none. Intervals restart at the start of the active hours, other timings are evaluated from there on.
+func (s Schedule) nextActive(n time.Time) time.Time {
+	for i := 0; i < maxInactiveRuns; i++ {
+		start := time.Date(n.Year(), n.Month(), n.Day(), s.ActiveFrom, 0, 0, 0, n.Location())
+		if !start.After(n) {
+			start = start.AddDate(0, 0, 1)
+		}
+		if _, ok := s.Timing.(every); ok {
+			return start
+		}
+
+		n = s.Timing.Next(start.Add(-time.Nanosecond))
+		if n.IsZero() || s.active(n) {
+			return n
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,35 @@
+package newsReader
+
+// stopwords are frequent German function words which never start, end or join a keyphrase.
+var stopwords = set(
+	[]string{
+		"aber", "alle", "allem", "allen", "aller", "alles", "als", "also", "am", "an", "ander", "andere", "anderem",
+		"anderen", "anderer", "anderes", "anders", "auch", "auf", "aus", "bei", "beim", "bereits", "bin", "bis",
+		"bisher", "bist", "bzw", "da", "dabei", "dadurch", "dafür", "dagegen", "daher", "damit", "danach", "dann",
+		"daran", "darauf", "darf", "darin", "darum", "darüber", "das", "dass", "davon", "dazu", "dein", "deine",
+		"dem", "den", "denen", "denn", "dennoch", "der", "deren", "derzeit", "des", "deshalb", "dessen", "die",
+		"dies", "diese", "diesem", "diesen", "dieser", "dieses", "doch", "dort", "du", "durch", "eben", "ein",
+		"eine", "einem", "einen", "einer", "eines", "einige", "einigen", "einmal", "er", "erst", "erste", "ersten",
+		"es", "etwa", "etwas", "euch", "euer", "für", "gab", "gar", "gegen", "gegenüber", "geht", "gewesen", "gibt",
+		"ging", "habe", "haben", "hat", "hatte", "hatten", "hier", "hin", "hinter", "ich", "ihm", "ihn", "ihnen",
+		"ihr", "ihre", "ihrem", "ihren", "ihrer", "ihres", "im", "immer", "in", "indem", "ins", "ist", "ja", "jede",
+		"jedem", "jeden", "jeder", "jedes", "jedoch", "jetzt", "kann", "kein", "keine", "keinem", "keinen",
+		"keiner", "können", "könnte", "man", "manche", "mehr", "mein", "meine", "mich", "mir", "mit", "muss",
+		"müssen", "nach", "nachdem", "neben", "nicht", "nichts", "noch", "nun", "nur", "ob", "oder", "ohne",
+		"schon", "sehr", "sei", "seien", "sein", "seine", "seinem", "seinen", "seiner", "seit", "selbst", "sich",
+		"sie", "sind", "so", "soll", "sollen", "sollte", "sondern", "sowie", "später", "statt", "um", "und", "uns",
+		"unser", "unsere", "unter", "viel", "viele", "vom", "von", "vor", "wann", "war", "waren", "warum", "was",
+		"weil", "weiter", "weitere", "welche", "welchem", "welchen", "welcher", "wenn", "wer", "werde", "werden",
+		"wie", "wieder", "will", "wir", "wird", "wo", "wohl", "wollen", "worden", "wurde", "wurden", "während",
+		"zu", "zum", "zur", "zwar", "zwischen", "über", "uhr", "sagte", "sagt", "heute", "gestern", "morgen",
+		"laut", "zudem", "sowohl", "demnach", "insgesamt", "rund", "mehrere", "neue", "neuen", "neuer", "neues",
+	},
+)
+
+func set(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
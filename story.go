@@ -0,0 +1,399 @@
+package newsReader
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Story event types.
+const (
+	StoryCreated = "storyCreated"
+	StoryUpdated = "storyUpdated"
+)
+
+// Story groups the articles about the same news event across sources.
+type Story struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Sources   []string  `json:"sources"`
+	Articles  []string  `json:"articles"`
+
+	// Meta is the metadata of the article event which changed the story.
+	Meta Metadata `json:"-"`
+}
+
+type StoryPublisher interface {
+	PublishStory(s Story, eType string) error
+}
+
+// Clusterer is a Processor assigning every article to a story. Articles are compared by the
+// cosine similarity of their tf-idf weighted terms and entities to the centroid of every story
+// active within the window. An article joins the most similar story if the similarity reaches
+// the threshold, otherwise it starts a new story. Stories are published by the Publisher of the
+// Clusterer once their articles have been published.
+type Clusterer struct {
+	pub       StoryPublisher
+	threshold float64
+	window    time.Duration
+	log       *zap.SugaredLogger
+
+	mu       sync.Mutex
+	df       map[string]int
+	docs     int
+	stories  map[string]*story
+	assigned map[string]string
+}
+
+type vector map[string]float64
+
+type story struct {
+	Story
+	members  map[string]vector
+	titles   map[string]string
+	hosts    map[string]string
+	centroid vector
+	// published are the IDs of the published articles of the story, created is set once the story has been.
+	published map[string]bool
+	created   bool
+}
+
+func NewClusterer(pub StoryPublisher, threshold float64, window time.Duration, l *zap.SugaredLogger) *Clusterer {
+	return &Clusterer{
+		pub:       pub,
+		threshold: threshold,
+		window:    window,
+		log:       l,
+		df:        make(map[string]int),
+		stories:   make(map[string]*story),
+		assigned:  make(map[string]string),
+	}
+}
+
+func (c *Clusterer) Name() string {
+	return "Story"
+}
+
+func (c *Clusterer) Version() string {
+	return "tfidf-1"
+}
+
+func (c *Clusterer) Reads() []Field {
+	return []Field{FieldTitle, FieldBody, FieldPers, FieldLocs, FieldOrgs, FieldUrl, FieldCollected}
+}
+
+func (c *Clusterer) Writes() []Field {
+	return []Field{FieldStory}
+}
+
+// Process assigns a to a story.
+func (c *Clusterer) Process(a Article) (Article, error) {
+	c.mu.Lock()
+	s := c.assign(a, "")
+	c.mu.Unlock()
+
+	c.log.Debugw("assigned story", "method", "Process", "articleID", a.ID, "storyID", s.ID)
+	a.Story = s.ID
+	return a, nil
+}
+
+// Warm restores the stories of the window from articles which have been assigned to stories before,
+// e.g. from all preprocessed articles after a restart. Src is replayed completely.
+func (c *Clusterer) Warm(src Source) error {
+	return Warm(src, c.log, c)
+}
+
+// Restore adds a to the story it has been assigned to before if the story is active within the window.
+func (c *Clusterer) Restore(a Article) error {
+	if len(a.Story) == 0 || time.Since(articleTime(a)) > c.window {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.assign(a, a.Story)
+	s.published[a.ID] = true
+	s.created = true
+	return nil
+}
+
+// Publisher returns a Publisher publishing articles to next and then the created or updated story
+// of every published article, so stories only reference published articles.
+func (c *Clusterer) Publisher(next Publisher) Publisher {
+	return storyPublisher{next: next, c: c}
+}
+
+type storyPublisher struct {
+	next Publisher
+	c    *Clusterer
+}
+
+func (p storyPublisher) Publish(a Article) error {
+	err := p.next.Publish(a)
+	if err != nil {
+		return err
+	}
+
+	err = p.c.publish(a)
+	if err != nil {
+		// the story is published with its next article
+		p.c.log.Errorw("could not publish story", "method", "Publish", "articleID", a.ID, "errMsg", err)
+	}
+	return nil
+}
+
+// publish publishes the story of the published article a.
+func (c *Clusterer) publish(a Article) error {
+	if len(a.Story) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	s, ok := c.stories[a.Story]
+	if !ok || c.assigned[a.ID] != a.Story {
+		c.mu.Unlock()
+		return nil
+	}
+	s.published[a.ID] = true
+	eType := StoryUpdated
+	if !s.created {
+		eType = StoryCreated
+		s.created = true
+	}
+	cp := s.snapshot()
+	c.mu.Unlock()
+
+	cp.Meta = a.Meta
+	err := c.pub.PublishStory(cp, eType)
+	if err != nil {
+		if eType == StoryCreated {
+			c.mu.Lock()
+			s.created = false
+			c.mu.Unlock()
+		}
+		return fmt.Errorf("could not publish story=%s of article=%s, %w", cp.ID, a.ID, err)
+	}
+
+	c.log.Debugw("published story", "method", "publish", "articleID", a.ID, "storyID", cp.ID, "eventType", eType)
+	return nil
+}
+
+// assign adds a to the story with id, the most similar story or a new story and returns it.
+// Callers must hold c.mu.
+func (c *Clusterer) assign(a Article, id string) *story {
+	t := articleTime(a)
+	c.evict(t)
+
+	terms := tokens(a)
+	if _, ok := c.assigned[a.ID]; !ok {
+		c.docs++
+		for term := range terms {
+			c.df[term]++
+		}
+		if c.docs > maxDocs {
+			c.age()
+		}
+	}
+	v := c.weigh(terms)
+
+	if len(id) == 0 {
+		id = c.assigned[a.ID]
+	}
+	s, ok := c.stories[id]
+	if !ok && len(id) == 0 {
+		s = c.nearest(v)
+	}
+
+	if s == nil {
+		if len(id) == 0 {
+			id = fmt.Sprintf("story-%s", strings.TrimPrefix(a.ID, "article-"))
+		}
+		s = &story{
+			Story:     Story{ID: id, FirstSeen: t, LastSeen: t},
+			members:   make(map[string]vector),
+			titles:    make(map[string]string),
+			hosts:     make(map[string]string),
+			published: make(map[string]bool),
+		}
+		c.stories[id] = s
+	}
+
+	s.add(a, v, t)
+	c.assigned[a.ID] = s.ID
+	return s
+}
+
+// nearest returns the story most similar to v if its similarity reaches the threshold.
+func (c *Clusterer) nearest(v vector) *story {
+	var best *story
+	max := c.threshold
+	for _, s := range c.stories {
+		sim := cosine(v, s.centroid)
+		if sim >= max {
+			best, max = s, sim
+		}
+	}
+	return best
+}
+
+// evict drops stories without articles within the window before t.
+func (c *Clusterer) evict(t time.Time) {
+	for id, s := range c.stories {
+		if t.Sub(s.LastSeen) <= c.window {
+			continue
+		}
+		for aID := range s.members {
+			delete(c.assigned, aID)
+		}
+		delete(c.stories, id)
+	}
+}
+
+// age halves all counts of the df table like KeywordExtractor.age, so recent articles weigh more and rare
+// terms are dropped. Callers must hold c.mu.
+func (c *Clusterer) age() {
+	c.docs /= 2
+	halve(c.df)
+	c.log.Infow("aged df table", "method", "assign", "numArticles", c.docs, "numTerms", len(c.df))
+}
+
+// weigh returns the normalized tf-idf vector of the term frequencies tf with smoothed idf, so terms
+// of every document keep a weight.
+func (c *Clusterer) weigh(tf map[string]int) vector {
+	v := make(vector, len(tf))
+	for term, n := range tf {
+		v[term] = float64(n) * (math.Log(float64(1+c.docs)/float64(1+c.df[term])) + 1)
+	}
+	return v.normalize()
+}
+
+func (s *story) add(a Article, v vector, t time.Time) {
+	if _, ok := s.members[a.ID]; !ok {
+		s.Articles = append(s.Articles, a.ID)
+	}
+	s.members[a.ID] = v
+	s.titles[a.ID] = a.Title
+
+	if t.Before(s.FirstSeen) {
+		s.FirstSeen = t
+	}
+	if t.After(s.LastSeen) {
+		s.LastSeen = t
+	}
+
+	u, err := url.Parse(a.Url)
+	if err == nil {
+		s.hosts[a.ID] = u.Host
+	}
+
+	s.centroid = vector{}
+	for _, m := range s.members {
+		for term, w := range m {
+			s.centroid[term] += w
+		}
+	}
+	s.centroid = s.centroid.normalize()
+
+	// the representative title is the title of the article closest to the centroid
+	max := -1.0
+	for _, id := range s.Articles {
+		sim := cosine(s.members[id], s.centroid)
+		if sim > max {
+			max, s.Title = sim, s.titles[id]
+		}
+	}
+}
+
+// snapshot returns a copy of the story with its published articles and their sources only.
+func (s *story) snapshot() Story {
+	cp := s.Story
+	cp.Articles, cp.Sources = nil, nil
+	for _, id := range s.Articles {
+		if !s.published[id] {
+			continue
+		}
+		cp.Articles = append(cp.Articles, id)
+		if host := s.hosts[id]; len(host) != 0 && !contains(cp.Sources, host) {
+			cp.Sources = append(cp.Sources, host)
+		}
+	}
+	sort.Strings(cp.Sources)
+	return cp
+}
+
+func (v vector) normalize() vector {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := math.Sqrt(sum)
+	for term, w := range v {
+		v[term] = w / norm
+	}
+	return v
+}
+
+// cosine returns the cosine similarity of the normalized vectors a and b.
+func cosine(a, b vector) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var sim float64
+	for term, w := range a {
+		sim += w * b[term]
+	}
+	return sim
+}
+
+// tokens counts the terms of title and body without stopwords and the entities of a. Title terms count twice,
+// entities thrice.
+func tokens(a Article) map[string]int {
+	tf := make(map[string]int)
+	count := func(s string, weight int) {
+		for _, w := range words(s) {
+			if len([]rune(w)) < 3 || stopwords[w] {
+				continue
+			}
+			tf[w] += weight
+		}
+	}
+	count(a.Title, 2)
+	count(a.Body, 1)
+
+	for _, ee := range [][]string{a.Pers, a.Locs, a.Orgs} {
+		for _, e := range ee {
+			tf[fmt.Sprintf("entity:%s", strings.ToLower(e))] += 3
+		}
+	}
+	return tf
+}
+
+// articleTime returns the time a has been collected, falling back to the time its event has been recorded.
+func articleTime(a Article) time.Time {
+	t := collectedAt(a)
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
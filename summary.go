@@ -0,0 +1,280 @@
+package newsReader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+// Summary methods recorded in Article.SummaryMethod.
+const (
+	AbstractiveSummary = "abstractive"
+	ExtractiveSummary  = "extractive"
+)
+
+const (
+	damping       = 0.85
+	maxIterations = 50
+	convergence   = 1e-4
+)
+
+// TextRank is a Processor filling Article.Summary with the n most central sentences of the body in their
+// original order. Sentences are ranked by TextRank over a graph weighted by their shared words.
+type TextRank struct {
+	n   int
+	log *zap.SugaredLogger
+}
+
+func NewTextRank(n int, l *zap.SugaredLogger) *TextRank {
+	return &TextRank{n: n, log: l}
+}
+
+func (t TextRank) Name() string {
+	return "Summary"
+}
+
+func (t TextRank) Version() string {
+	return "textrank-1"
+}
+
+func (t TextRank) Reads() []Field {
+	return []Field{FieldBody}
+}
+
+func (t TextRank) Writes() []Field {
+	return []Field{FieldSummary, FieldSummaryMethod}
+}
+
+func (t TextRank) Process(a Article) (Article, error) {
+	ss := Sentences(a.Body)
+	if len(ss) == 0 {
+		return a, fmt.Errorf("could not summarize article=%s without sentences", a.ID)
+	}
+
+	t.log.Debugw("rank sentences", "method", "Process", "articleID", a.ID, "numSentences", len(ss))
+	a.Summary = strings.Join(t.rank(ss), " ")
+	a.SummaryMethod = ExtractiveSummary
+	return a, nil
+}
+
+// rank returns the n highest ranked sentences of ss in their original order.
+func (t TextRank) rank(ss []string) []string {
+	if len(ss) <= t.n {
+		return ss
+	}
+
+	bags := make([]map[string]bool, len(ss))
+	for i, s := range ss {
+		bags[i] = make(map[string]bool)
+		for _, w := range words(s) {
+			if !stopwords[w] && len([]rune(w)) > 2 {
+				bags[i][w] = true
+			}
+		}
+	}
+
+	// edges are weighted by the shared words normalized by the sentence lengths
+	weights := make([][]float64, len(ss))
+	sums := make([]float64, len(ss))
+	for i := range ss {
+		weights[i] = make([]float64, len(ss))
+		for j := range ss {
+			if i == j || len(bags[i]) < 2 || len(bags[j]) < 2 {
+				continue
+			}
+			shared := 0
+			for w := range bags[i] {
+				if bags[j][w] {
+					shared++
+				}
+			}
+			weights[i][j] = float64(shared) / (math.Log(float64(len(bags[i]))) + math.Log(float64(len(bags[j]))))
+			sums[i] += weights[i][j]
+		}
+	}
+
+	scores := make([]float64, len(ss))
+	for i := range scores {
+		scores[i] = 1
+	}
+	for it := 0; it < maxIterations; it++ {
+		next := make([]float64, len(ss))
+		delta := 0.0
+		for i := range ss {
+			var in float64
+			for j := range ss {
+				if weights[j][i] != 0 {
+					in += weights[j][i] / sums[j] * scores[j]
+				}
+			}
+			next[i] = 1 - damping + damping*in
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores = next
+		if delta < convergence {
+			break
+		}
+	}
+
+	idx := make([]int, len(ss))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return scores[idx[i]] > scores[idx[j]] })
+	idx = idx[:t.n]
+	sort.Ints(idx)
+
+	summary := make([]string, 0, t.n)
+	for _, i := range idx {
+		summary = append(summary, ss[i])
+	}
+	return summary
+}
+
+// abbreviations are German abbreviations whose trailing dot does not end a sentence.
+var abbreviations = set(
+	[]string{
+		"abs.", "abt.", "bzw.", "ca.", "chr.", "d.h.", "dr.", "evtl.", "ff.", "fr.", "ggf.", "hr.", "inkl.", "jh.",
+		"max.", "min.", "mio.", "mrd.", "nr.", "o.ä.", "prof.", "s.", "sog.", "st.", "str.", "tel.", "u.a.", "usw.",
+		"v.a.", "vgl.", "z.b.", "z.t.", "zzgl.", "bspw.", "etc.", "gem.", "jan.", "feb.", "apr.", "aug.", "sept.",
+		"okt.", "nov.", "dez.",
+	},
+)
+
+// Sentences splits text into sentences at paragraph breaks and at '.', '!' and '?' followed by a capitalized
+// word. Dots of German abbreviations, ordinals and dates like "3. Oktober" or "19.10." and initials do not
+// end a sentence.
+func Sentences(text string) []string {
+	var ss []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		tokens := strings.Fields(paragraph)
+		start := 0
+		for i, tok := range tokens {
+			if i+1 < len(tokens) && !(endsSentence(tok) && startsSentence(tokens[i+1])) {
+				continue
+			}
+			ss = append(ss, strings.Join(tokens[start:i+1], " "))
+			start = i + 1
+		}
+	}
+	return ss
+}
+
+func endsSentence(tok string) bool {
+	tok = strings.TrimRightFunc(tok, closing)
+	switch {
+	case strings.HasSuffix(tok, "!"), strings.HasSuffix(tok, "?"):
+		return true
+	case !strings.HasSuffix(tok, "."):
+		return false
+	case abbreviations[strings.ToLower(tok)]:
+		return false
+	}
+
+	// ordinals, dates and initials
+	word := strings.TrimLeftFunc(strings.TrimRight(tok, "."), opening)
+	if len(word) == 0 || len([]rune(word)) == 1 {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsDigit(r) && r != '.' {
+			return true
+		}
+	}
+	return false
+}
+
+func startsSentence(tok string) bool {
+	for _, r := range strings.TrimLeftFunc(tok, opening) {
+		return unicode.IsUpper(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+func opening(r rune) bool {
+	return strings.ContainsRune("\"'„“‚‘»«([", r)
+}
+
+func closing(r rune) bool {
+	return strings.ContainsRune("\"'“”‘’«»)]", r)
+}
+
+// Fallback is a Processor trying its processors in order until one succeeds, e.g. an extractive
+// summarizer behind an abstractive one which depends on a remote model.
+type Fallback struct {
+	pp  []Processor
+	log *zap.SugaredLogger
+}
+
+func NewFallback(l *zap.SugaredLogger, pp ...Processor) *Fallback {
+	return &Fallback{pp: pp, log: l}
+}
+
+// Name returns the name of the first processor.
+func (f Fallback) Name() string {
+	if len(f.pp) == 0 {
+		return "Fallback"
+	}
+	return f.pp[0].Name()
+}
+
+// Version returns the versions of all processors separated by '|', see Delegate for the version of a result.
+func (f Fallback) Version() string {
+	vv := make([]string, 0, len(f.pp))
+	for _, p := range f.pp {
+		vv = append(vv, version(p))
+	}
+	return strings.Join(vv, "|")
+}
+
+// Reads returns the fields read by any of the processors.
+func (f Fallback) Reads() []Field {
+	var ff []Field
+	for _, p := range f.pp {
+		ff = union(ff, reads(p))
+	}
+	return ff
+}
+
+// Writes returns the fields written by any of the processors.
+func (f Fallback) Writes() []Field {
+	var ff []Field
+	for _, p := range f.pp {
+		ff = union(ff, writes(p))
+	}
+	return ff
+}
+
+func (f Fallback) Process(a Article) (Article, error) {
+	res, _, err := f.Delegate(a)
+	return res, err
+}
+
+// Delegate returns the result of the first processor which succeeds and that processor.
+func (f Fallback) Delegate(a Article) (Article, Processor, error) {
+	err := errors.New("no processor succeeded")
+	for i, p := range f.pp {
+		res, pErr := p.Process(a)
+		if pErr == nil {
+			return res, p, nil
+		}
+
+		err = fmt.Errorf("%v, %w", pErr.Error(), err)
+		if i+1 < len(f.pp) {
+			f.log.Warnw(
+				"process error, fall back",
+				"method", "Process",
+				"articleID", a.ID,
+				"processorName", p.Name(),
+				"fallbackVersion", version(f.pp[i+1]),
+				"errMsg", pErr.Error(),
+			)
+		}
+	}
+	return a, nil, err
+}
@@ -0,0 +1,168 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/disk"
+	"newsReader/memory"
+	"newsReader/mock"
+)
+
+func TestCachedProcess(t *testing.T) {
+	dir, err := disk.NewResultCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		cache newsReader.ResultCache
+	}{
+		{name: "memory", cache: memory.NewResultCache(10)},
+		{name: "disk", cache: dir},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				calls := 0
+				ner := &mock.FieldProcessor{
+					ProcessorName: "ner",
+					ReadFields:    []newsReader.Field{newsReader.FieldBody},
+					WriteFields:   []newsReader.Field{newsReader.FieldPers},
+					ModelVersion:  "1.0",
+					ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+						calls++
+						if a.Body == "fail" {
+							return a, errors.New("torchServe unavailable")
+						}
+						a.Pers = []string{a.Body}
+						return a, nil
+					},
+				}
+				c, err := newsReader.NewCached(ner, test.cache, zap.NewNop().Sugar())
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+
+				first, err := c.Process(newsReader.Article{ID: "article-1", Title: "a", Body: "Merkel"})
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				// a recollected article with unchanged body but new title
+				second, err := c.Process(newsReader.Article{ID: "article-1", Title: "b", Body: "Merkel"})
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				if calls != 1 {
+					t.Errorf("want 1 call for unchanged body, got %v", calls)
+				}
+				if !reflect.DeepEqual(second.Pers, first.Pers) || second.Title != "b" {
+					t.Errorf("want cached pers on the new article, got %+v", second)
+				}
+
+				_, _ = c.Process(newsReader.Article{ID: "article-1", Body: "Scholz"})
+				if calls != 2 {
+					t.Errorf("want call for changed body, got %v calls", calls)
+				}
+
+				_, _ = c.Process(newsReader.Article{ID: "article-2", Body: "fail"})
+				_, err = c.Process(newsReader.Article{ID: "article-2", Body: "fail"})
+				if err == nil || calls != 4 {
+					t.Errorf("want failures not cached, got err=%v after %v calls", err, calls)
+				}
+			},
+		)
+	}
+}
+
+func TestCachedWithoutVersion(t *testing.T) {
+	ner := &mock.FieldProcessor{ProcessorName: "ner"}
+	_, err := newsReader.NewCached(ner, memory.NewResultCache(10), zap.NewNop().Sugar())
+	if err == nil {
+		t.Errorf("want error for processor without version")
+	}
+}
+
+func TestMemoryResultCacheEviction(t *testing.T) {
+	c := memory.NewResultCache(2)
+	_ = c.Save("a", newsReader.Article{ID: "a"})
+	_ = c.Save("b", newsReader.Article{ID: "b"})
+	_, _ = c.Load("a")
+	_ = c.Save("c", newsReader.Article{ID: "c"})
+
+	_, err := c.Load("b")
+	if !errors.Is(err, newsReader.ErrNotFound) {
+		t.Errorf("want least recently used result evicted, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		a, err := c.Load(key)
+		if err != nil || a.ID != key {
+			t.Errorf("want result=%s, got %+v, %v", key, a, err)
+		}
+	}
+}
+
+func TestPageCache(t *testing.T) {
+	dir, err := disk.NewPageCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		cache newsReader.PageCache
+	}{
+		{name: "memory", cache: memory.NewPageCache(10)},
+		{name: "disk", cache: dir},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				url := "https://www.tagesschau.de/inland/article-1.html"
+				_, err := test.cache.Load(url)
+				if !errors.Is(err, newsReader.ErrNotFound) {
+					t.Errorf("want ErrNotFound for unknown url, got %v", err)
+				}
+
+				for _, want := range []newsReader.Page{
+					{ETag: `"v1"`, ContentType: "text/html", Body: []byte("<html></html>")},
+					{LastModified: "Mon, 19 Oct 2026 08:00:00 GMT"},
+				} {
+					err = test.cache.Save(url, want)
+					if err != nil {
+						t.Fatalf("want no error, got %v", err)
+					}
+					got, err := test.cache.Load(url)
+					if err != nil || !reflect.DeepEqual(got, want) {
+						t.Errorf("want page %+v, got %+v, %v", want, got, err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestMemoryPageCacheEviction(t *testing.T) {
+	c := memory.NewPageCache(2)
+	_ = c.Save("a", newsReader.Page{ETag: "a"})
+	_ = c.Save("b", newsReader.Page{ETag: "b"})
+	_, _ = c.Load("a")
+	_ = c.Save("c", newsReader.Page{ETag: "c"})
+
+	_, err := c.Load("b")
+	if !errors.Is(err, newsReader.ErrNotFound) {
+		t.Errorf("want least recently used page evicted, got %v", err)
+	}
+	for _, url := range []string{"a", "c"} {
+		p, err := c.Load(url)
+		if err != nil || p.ETag != url {
+			t.Errorf("want page=%s, got %+v, %v", url, p, err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package newsReader_test
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 	"newsReader"
@@ -19,13 +21,14 @@ func TestCollectorCollect(t *testing.T) {
 		PublishFn      func(a newsReader.Article) error
 		PublishInvoked bool
 
-		wantErr bool
+		wantPublished int
+		wantErrors    int
 	}{
 		{
 			Name: "pass",
 
 			CrawlFn: func() ([]newsReader.Article, error) {
-				return []newsReader.Article{{}, {}}, nil
+				return []newsReader.Article{{Title: "a"}, {Title: "b"}}, nil
 			},
 			CrawlInvoked: true,
 
@@ -34,7 +37,7 @@ func TestCollectorCollect(t *testing.T) {
 			},
 			PublishInvoked: true,
 
-			wantErr: false,
+			wantPublished: 2,
 		},
 		{
 			Name: "Crawler error",
@@ -49,7 +52,7 @@ func TestCollectorCollect(t *testing.T) {
 			},
 			PublishInvoked: false,
 
-			wantErr: true,
+			wantErrors: 1,
 		},
 		{
 			Name: "publisher error",
@@ -64,7 +67,7 @@ func TestCollectorCollect(t *testing.T) {
 			},
 			PublishInvoked: true,
 
-			wantErr: true,
+			wantErrors: 1,
 		},
 	}
 
@@ -96,10 +99,18 @@ func TestCollectorCollect(t *testing.T) {
 						return
 					}
 
-					err = clr.RunOnce()
-
-					if (err != nil) != test.wantErr {
-						t.Fatalf("want collect to return none nil error, got %v", err)
+					r, err := clr.RunOnce()
+					if err != nil {
+						t.Fatalf("want no error, got %v", err)
+					}
+					if len(r.Crawlers) != 1 {
+						t.Fatalf("want report of 1 crawler, got %v", len(r.Crawlers))
+					}
+					if r.Crawlers[0].Published != test.wantPublished {
+						t.Fatalf("want %v published, got %v", test.wantPublished, r.Crawlers[0].Published)
+					}
+					if len(r.Crawlers[0].Errors) != test.wantErrors {
+						t.Fatalf("want %v errors, got %v", test.wantErrors, r.Crawlers[0].Errors)
 					}
 
 					if test.CrawlInvoked != c.CrawlInvoked {
@@ -114,3 +125,354 @@ func TestCollectorCollect(t *testing.T) {
 		)
 	}
 }
+
+func TestCollectorCorrelation(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	c := &mock.Crawler{
+		CrawlFn: func() ([]newsReader.Article, error) {
+			return []newsReader.Article{{Title: "aa"}, {Title: "bb"}}, nil
+		},
+	}
+
+	ids := make(map[string]bool)
+	p := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			ids[a.Meta.CorrelationID] = true
+			return nil
+		},
+	}
+
+	clr, err := newsReader.NewCollectorBuilder().Crawlers(c).Publisher(p).Logger(zapper.Sugar()).Build()
+	if err != nil {
+		t.Fatalf("could not get new collector")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = clr.RunOnce()
+		if err != nil {
+			t.Fatalf("want no error, got=%v", err)
+		}
+	}
+
+	if len(ids) != 2 {
+		t.Fatalf("want one correlationID per run, got=%v", ids)
+	}
+	if ids[""] {
+		t.Fatalf("want correlationID to be set")
+	}
+}
+
+func TestCollectorIsolation(t *testing.T) {
+	failing := &mock.Crawler{
+		CrawlerName: "failing",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			return nil, fmt.Errorf("site down")
+		},
+	}
+	panicking := &mock.Crawler{
+		CrawlerName: "panicking",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			panic("unexpected html")
+		},
+	}
+	working := &mock.Crawler{
+		CrawlerName: "working",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			return []newsReader.Article{{ID: "aa"}, {ID: "bb"}, {ID: "aa"}, {ID: "cc"}}, nil
+		},
+	}
+	p := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			switch a.ID {
+			case "bb":
+				return fmt.Errorf("some Publisher error")
+			case "cc":
+				return &newsReader.WrongExpectedVersionError{StreamID: a.ID}
+			}
+			return nil
+		},
+	}
+
+	clr, err := newsReader.NewCollectorBuilder().
+		Crawlers(failing, panicking, working).
+		Publisher(p).
+		NumWorker(2).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("could not get new collector")
+	}
+
+	r, err := clr.RunOnce()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if r.Failed() != 3 {
+		t.Errorf("want 3 failed crawlers, got %v", r.Failed())
+	}
+
+	got := r.Crawlers[2]
+	if got.Found != 4 || got.Published != 1 || got.Skipped != 2 || len(got.Errors) != 1 {
+		t.Errorf("want found=4, published=1, skipped=2 and 1 error, got %+v", got)
+	}
+	if len(r.Crawlers[1].Errors) != 1 {
+		t.Errorf("want panic recorded as error, got %+v", r.Crawlers[1])
+	}
+}
+
+func TestCollectorDuplicatesWithoutID(t *testing.T) {
+	crawler := &mock.Crawler{
+		CrawlerName: "teaser",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			// the same article linked from two teasers
+			return []newsReader.Article{
+				{Url: "https://a.de/1", Title: "a"},
+				{Url: "https://a.de/2", Title: "b"},
+				{Url: "https://a.de/1", Title: "a"},
+			}, nil
+		},
+	}
+	var published []string
+	p := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			published = append(published, a.ID)
+			return nil
+		},
+	}
+
+	clr, err := newsReader.NewCollectorBuilder().
+		Crawlers(crawler).
+		Publisher(p).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("could not get new collector")
+	}
+
+	r, err := clr.RunOnce()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	got := r.Crawlers[0]
+	if got.Found != 3 || got.Published != 2 || got.Skipped != 1 {
+		t.Errorf("want found=3, published=2, skipped=1, got %+v", got)
+	}
+	want := []string{
+		newsReader.ArticleID(newsReader.Article{Url: "https://a.de/1", Title: "a"}),
+		newsReader.ArticleID(newsReader.Article{Url: "https://a.de/2", Title: "b"}),
+	}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("want published=%v, got %v", want, published)
+	}
+}
+
+func TestCollectorRun(t *testing.T) {
+	release := make(chan struct{})
+	crawled := make(chan string, 10)
+	slow := &mock.Crawler{
+		CrawlerName: "slow",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			crawled <- "slow"
+			<-release
+			return nil, nil
+		},
+	}
+	fast := &mock.Crawler{
+		CrawlerName: "fast",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			select {
+			case crawled <- "fast":
+			default:
+			}
+			return nil, nil
+		},
+	}
+	p := &mock.Publisher{PublishFn: func(a newsReader.Article) error { return nil }}
+
+	clr, err := newsReader.NewCollectorBuilder().
+		Crawlers(slow, fast).
+		Schedule("fast", newsReader.Schedule{Timing: newsReader.Every(time.Millisecond)}).
+		Publisher(p).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("could not get new collector, %v", err)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		clr.Run(stop)
+		close(done)
+	}()
+
+	// the fast crawler runs on its schedule while the unscheduled slow crawler waits for a trigger
+	want := map[string]int{"fast": 2}
+	got := make(map[string]int)
+	for got["fast"] < want["fast"] {
+		got[<-crawled]++
+	}
+	if got["slow"] != 0 {
+		t.Fatalf("want unscheduled crawler not run, got %v", got)
+	}
+
+	err = clr.Trigger("slow")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	for name := <-crawled; name != "slow"; name = <-crawled {
+	}
+
+	// a crawler never overlaps with itself
+	r, err := clr.RunOnce()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if len(r.Crawlers[0].Errors) != 1 {
+		t.Errorf("want running crawler skipped, got %+v", r.Crawlers[0])
+	}
+
+	close(release)
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("want Run to return after stop")
+	}
+
+	if clr.Trigger("unknown") == nil {
+		t.Errorf("want error for unknown crawler")
+	}
+}
+
+func TestCollectorRunInterval(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	started := make(chan time.Time, 10)
+	c := &mock.Crawler{
+		CrawlerName: "scheduled",
+		CrawlFn: func() ([]newsReader.Article, error) {
+			started <- time.Now()
+			// the crawl takes more than half of the interval
+			time.Sleep(60 * time.Millisecond)
+			return nil, fmt.Errorf("site down")
+		},
+	}
+	reports := make(chan newsReader.CrawlReport, 10)
+
+	clr, err := newsReader.NewCollectorBuilder().
+		Crawlers(c).
+		Schedule("scheduled", newsReader.Schedule{Timing: newsReader.Every(interval)}).
+		OnReport(func(r newsReader.CrawlReport) { reports <- r }).
+		Publisher(&mock.Publisher{PublishFn: func(a newsReader.Article) error { return nil }}).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("could not get new collector, %v", err)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		clr.Run(stop)
+		close(done)
+	}()
+
+	var tt []time.Time
+	for len(tt) < 3 {
+		tt = append(tt, <-started)
+	}
+	close(stop)
+	<-done
+
+	// runs are due every interval from the previous due time, not every interval after a crawl
+	if span := tt[2].Sub(tt[0]); span > 2*interval+50*time.Millisecond {
+		t.Errorf("want 3 runs within %v, got %v", 2*interval, span)
+	}
+	r := <-reports
+	if r.Crawler != "scheduled" || len(r.Errors) != 1 {
+		t.Errorf("want report of the failed crawl, got %+v", r)
+	}
+}
+
+func TestCollectorStream(t *testing.T) {
+	published := make(chan string)
+	s := &mock.StreamCrawler{
+		StreamFn: func(c chan<- newsReader.Article) error {
+			for _, id := range []string{"aa", "bb"} {
+				c <- newsReader.Article{ID: id}
+				// the article is published before the crawl continues
+				select {
+				case got := <-published:
+					if got != id {
+						return fmt.Errorf("want published=%s, got %s", id, got)
+					}
+				case <-time.After(time.Second):
+					return fmt.Errorf("article=%s not published while crawling", id)
+				}
+			}
+			return fmt.Errorf("site down")
+		},
+	}
+	p := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			published <- a.ID
+			return nil
+		},
+	}
+
+	clr, err := newsReader.NewCollectorBuilder().StreamCrawlers(s).Publisher(p).Logger(zap.NewNop().Sugar()).Build()
+	if err != nil {
+		t.Fatalf("could not get new collector, %v", err)
+	}
+
+	r, err := clr.RunOnce()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	got := r.Crawlers[0]
+	if got.Found != 2 || got.Published != 2 || len(got.Errors) != 1 || got.Errors[0].Error() != "could not crawl resource=mockStreamCrawler, site down" {
+		t.Errorf("want 2 published articles and the crawl error, got %+v", got)
+	}
+}
+
+func TestCollectorStreamPanic(t *testing.T) {
+	s := &mock.StreamCrawler{
+		StreamFn: func(c chan<- newsReader.Article) error {
+			c <- newsReader.Article{ID: "aa"}
+			panic("parser broken")
+		},
+	}
+	p := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			return nil
+		},
+	}
+
+	clr, err := newsReader.NewCollectorBuilder().StreamCrawlers(s).Publisher(p).Logger(zap.NewNop().Sugar()).Build()
+	if err != nil {
+		t.Fatalf("could not get new collector, %v", err)
+	}
+
+	done := make(chan newsReader.Report)
+	go func() {
+		r, _ := clr.RunOnce()
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		got := r.Crawlers[0]
+		if got.Published != 1 || len(got.Errors) != 1 {
+			t.Errorf("want the published article and the panic as error, got %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("RunOnce did not return after the crawler panicked")
+	}
+}
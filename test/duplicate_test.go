@@ -0,0 +1,63 @@
+package newsReader_test
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/memory"
+)
+
+const agencyBody = `Berlin (dpa) - Die Bundesregierung hat sich nach langen Verhandlungen auf einen Haushalt für das
+kommende Jahr geeinigt. Finanzminister Lindner sagte am Abend in Berlin, die Schuldenbremse werde eingehalten.
+Die Opposition kritisierte die Einigung als unzureichend und kündigte Widerstand im Bundestag an. Der Entwurf soll
+in der kommenden Woche im Kabinett beraten werden.`
+
+func TestSimHash(t *testing.T) {
+	a, _ := newsReader.SimHash(agencyBody)
+	b, _ := newsReader.SimHash(agencyBody + " Mehr dazu in Kürze.")
+	c, _ := newsReader.SimHash("Starkregen hat in Teilen Bayerns zu Überschwemmungen geführt. Die Feuerwehr war im Dauereinsatz.")
+
+	if sim := newsReader.Similarity(a, b); sim < 0.9 {
+		t.Errorf("want similarity of near-duplicates >= 0.9, got %v", sim)
+	}
+	if sim := newsReader.Similarity(a, c); sim > 0.8 {
+		t.Errorf("want similarity of different texts <= 0.8, got %v", sim)
+	}
+	if _, ok := newsReader.SimHash(" - "); ok {
+		t.Errorf("want no fingerprint without words")
+	}
+}
+
+func TestDeduplicatorProcess(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	articles := []newsReader.Article{
+		{ID: "article-1", Body: agencyBody, Collected: t0.Format(time.RFC3339)},
+		{ID: "article-2", Body: agencyBody + " Mehr dazu in Kürze.", Collected: t0.Add(time.Hour).Format(time.RFC3339)},
+		{ID: "article-3", Body: "Starkregen in Bayern.", Collected: t0.Add(time.Hour).Format(time.RFC3339)},
+		// copy of the copy points to the earliest original
+		{ID: "article-4", Body: agencyBody + " Mehr dazu in Kürze.", Collected: t0.Add(2 * time.Hour).Format(time.RFC3339)},
+		// reprocessing the original keeps it original
+		{ID: "article-1", Body: agencyBody, Collected: t0.Format(time.RFC3339)},
+		// recollecting the original after its copies keeps it original
+		{ID: "article-1", Body: agencyBody, Collected: t0.Add(3 * time.Hour).Format(time.RFC3339)},
+		// recollecting a copy keeps it a copy of the earliest original
+		{ID: "article-2", Body: agencyBody + " Mehr dazu in Kürze.", Collected: t0.Add(4 * time.Hour).Format(time.RFC3339)},
+	}
+	want := []string{"", "article-1", "", "article-1", "", "", "article-1"}
+
+	d := newsReader.NewDeduplicator(memory.NewFingerprintStore(), 6, zap.NewNop().Sugar())
+	for i, a := range articles {
+		got, err := d.Process(a)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		if got.DuplicateOf != want[i] {
+			t.Errorf("want article %v duplicate of=%q, got %q", i, want[i], got.DuplicateOf)
+		}
+		if len(want[i]) != 0 && got.Similarity < 0.9 {
+			t.Errorf("want article %v similarity >= 0.9, got %v", i, got.Similarity)
+		}
+	}
+}
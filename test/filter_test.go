@@ -0,0 +1,115 @@
+package newsReader_test
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestFilters(t *testing.T) {
+	german := newsReader.Article{
+		Url:  "https://www.tagesschau.de/inland/a.html",
+		Body: "Die Regierung hat sich auf einen Haushalt geeinigt, der im Bundestag beraten wird.",
+		Pers: []string{"Scholz"},
+	}
+	english := newsReader.Article{
+		Url:  "https://www.bbc.co.uk/news/a",
+		Body: "The government agreed on a budget which will be debated in parliament.",
+	}
+
+	tests := []struct {
+		name   string
+		filter newsReader.Filter
+		arg    newsReader.Article
+		want   bool
+	}{
+		{name: "min body length", filter: newsReader.MinBodyLength(20), arg: german, want: true},
+		{name: "body too short", filter: newsReader.MinBodyLength(200), arg: german, want: false},
+		{name: "german", filter: newsReader.German(), arg: german, want: true},
+		{name: "not german", filter: newsReader.German(), arg: english, want: false},
+		{name: "not", filter: newsReader.Not(newsReader.German()), arg: english, want: true},
+		{name: "from hosts", filter: newsReader.FromHost("www.spiegel.de", "www.tagesschau.de"), arg: german, want: true},
+		{name: "from other host", filter: newsReader.FromHost("www.spiegel.de"), arg: english, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := test.filter(test.arg); got != test.want {
+					t.Errorf("want %v, got %v", test.want, got)
+				}
+			},
+		)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	ner := &mock.FieldProcessor{
+		ProcessorName: "ner",
+		ReadFields:    []newsReader.Field{newsReader.FieldBody},
+		WriteFields:   []newsReader.Field{newsReader.FieldPers, newsReader.FieldLocs},
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			a.Pers = []string{"Scholz"}
+			return a, nil
+		},
+	}
+
+	var processed newsReader.Article
+	opr, err := newsReader.NewOperatorBuilder().
+		Processors(ner).
+		Consumer(
+			&mock.Consumer{
+				ConsumeFn: func(c chan<- newsReader.Article) {
+					c <- newsReader.Article{ID: "a", Body: "Die Regierung hat sich auf einen Haushalt geeinigt."}
+					close(c)
+				},
+			},
+		).
+		Publisher(
+			&mock.Publisher{
+				PublishFn: func(a newsReader.Article) error {
+					processed = a
+					return nil
+				},
+			},
+		).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	err = opr.Run()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	changed := processed
+	changed.Body = "Der Bundestag hat den Haushalt beschlossen."
+	unprocessed := processed
+	unprocessed.Meta.Inputs = nil
+
+	tests := []struct {
+		name   string
+		filter newsReader.Filter
+		arg    newsReader.Article
+		want   bool
+	}{
+		{name: "entities present, body unchanged", filter: newsReader.Missing(ner, newsReader.FieldPers), arg: processed, want: false},
+		{name: "missing entities", filter: newsReader.Missing(ner, newsReader.FieldPers, newsReader.FieldLocs), arg: processed, want: true},
+		{name: "body changed", filter: newsReader.Missing(ner, newsReader.FieldPers), arg: changed, want: true},
+		{name: "not processed", filter: newsReader.Missing(ner, newsReader.FieldPers), arg: unprocessed, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := test.filter(test.arg); got != test.want {
+					t.Errorf("want %v, got %v", test.want, got)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,78 @@
+package newsReader_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestKeywordExtractorProcess(t *testing.T) {
+	corpus := []newsReader.Article{
+		{ID: "article-1", Title: "Wetter am Wochenende", Body: "Am Wochenende wird es sonnig. Die Regierung plant nichts."},
+		{ID: "article-2", Title: "Regierung im Bundestag", Body: "Die Regierung hat im Bundestag debattiert."},
+		{ID: "article-3", Title: "Fußball", Body: "Die Regierung schaut Fußball."},
+	}
+	src := &mock.Source{
+		ReplayFn: func(c chan<- newsReader.Article) error {
+			for _, a := range corpus {
+				c <- a
+			}
+			close(c)
+			return nil
+		},
+	}
+
+	k := newsReader.NewKeywordExtractor(3, zap.NewNop().Sugar())
+	err := k.Warm(src)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	got, err := k.Process(
+		newsReader.Article{
+			ID:    "article-4",
+			Title: "Europäische Zentralbank erhöht Leitzins",
+			Body: "Die Europäische Zentralbank hat den Leitzins erneut erhöht. Die Regierung begrüßte die " +
+				"Entscheidung der Europäische Zentralbank. Der Leitzins steigt damit auf vier Prozent.",
+		},
+	)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	want := []string{"Europäische Zentralbank", "Leitzins", "vier Prozent"}
+	if !reflect.DeepEqual(got.Keywords, want) {
+		t.Errorf("want keywords=%q, got %q", want, got.Keywords)
+	}
+}
+
+func TestKeywordExtractorLearnOnce(t *testing.T) {
+	k := newsReader.NewKeywordExtractor(1, zap.NewNop().Sugar())
+
+	// a re-crawled article must not make its words look common
+	for i := 0; i < 10; i++ {
+		_, err := k.Process(newsReader.Article{ID: "article-1", Body: "Haushalt."})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+	for _, id := range []string{"article-2", "article-3"} {
+		_, err := k.Process(newsReader.Article{ID: id, Body: "Bahn."})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+
+	got, err := k.Process(newsReader.Article{ID: "article-4", Body: "Bahn. Haushalt."})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	want := []string{"Haushalt"}
+	if !reflect.DeepEqual(got.Keywords, want) {
+		t.Errorf("want keywords=%q, got %q", want, got.Keywords)
+	}
+}
@@ -2,7 +2,12 @@ package newsReader_test
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 	"newsReader"
@@ -82,7 +87,7 @@ func TestOperatorRunOnce(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			Name: "process error",
+			Name: "optional process error",
 
 			ConsumeFn: func(c chan<- newsReader.Article) {
 				c <- newsReader.Article{Title: "aa"}
@@ -101,7 +106,7 @@ func TestOperatorRunOnce(t *testing.T) {
 			},
 			PublishInvoked: true,
 
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			Name: "publisher error",
@@ -184,3 +189,705 @@ func TestOperatorRunOnce(t *testing.T) {
 		)
 	}
 }
+
+func TestOperatorConflict(t *testing.T) {
+	conflict := &newsReader.WrongExpectedVersionError{StreamID: "aa", Expected: 1}
+
+	tests := []struct {
+		Name string
+
+		Policy newsReader.ConflictPolicy
+
+		LatestFn      func(id string) (newsReader.Article, bool, error)
+		LatestInvoked bool
+
+		PublishFn func(a newsReader.Article) error
+
+		wantPublished int
+		wantErr       bool
+	}{
+		{
+			Name:   "drop",
+			Policy: newsReader.DropOnConflict,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				return newsReader.Article{}, false, nil
+			},
+			LatestInvoked: false,
+			PublishFn: func(a newsReader.Article) error {
+				return conflict
+			},
+			wantPublished: 0,
+			wantErr:       false,
+		},
+		{
+			Name:   "retry pending",
+			Policy: newsReader.RetryOnConflict,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				return newsReader.Article{ID: id, Version: 2}, true, nil
+			},
+			LatestInvoked: true,
+			PublishFn: func(a newsReader.Article) error {
+				if a.Version < 2 {
+					return conflict
+				}
+				return nil
+			},
+			wantPublished: 1,
+			wantErr:       false,
+		},
+		{
+			Name:   "retry moved on",
+			Policy: newsReader.RetryOnConflict,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				return newsReader.Article{ID: id, Version: 2}, false, nil
+			},
+			LatestInvoked: true,
+			PublishFn: func(a newsReader.Article) error {
+				return conflict
+			},
+			wantPublished: 0,
+			wantErr:       false,
+		},
+		{
+			Name:   "retry exhausted",
+			Policy: newsReader.RetryOnConflict,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				return newsReader.Article{ID: id}, true, nil
+			},
+			LatestInvoked: true,
+			PublishFn: func(a newsReader.Article) error {
+				return conflict
+			},
+			wantPublished: 0,
+			wantErr:       false,
+		},
+		{
+			Name:   "reader error",
+			Policy: newsReader.RetryOnConflict,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				return newsReader.Article{}, false, errors.New("some reader error")
+			},
+			LatestInvoked: true,
+			PublishFn: func(a newsReader.Article) error {
+				return conflict
+			},
+			wantPublished: 0,
+			wantErr:       true,
+		},
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	logger := zapper.Sugar()
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				published := 0
+				c := &mock.Consumer{
+					ConsumeFn: func(c chan<- newsReader.Article) {
+						c <- newsReader.Article{ID: "aa", Version: 1}
+						close(c)
+					},
+					LatestFn: test.LatestFn,
+				}
+				pu := &mock.Publisher{
+					PublishFn: func(a newsReader.Article) error {
+						err := test.PublishFn(a)
+						if err == nil {
+							published++
+						}
+						return err
+					},
+				}
+
+				oprB := newsReader.NewOperatorBuilder()
+				opr, err := oprB.Publisher(pu).Consumer(c).Reader(c).OnConflict(test.Policy).Logger(logger).Build()
+				if err != nil {
+					t.Fatalf("could not get new operator")
+				}
+
+				err = opr.Run()
+
+				if (err != nil) != test.wantErr {
+					t.Fatalf("wanted return error=%v got=%v", test.wantErr, err)
+				}
+				if c.LatestInvoked != test.LatestInvoked {
+					t.Fatalf("want LatestInvoked=%v got=%v", test.LatestInvoked, c.LatestInvoked)
+				}
+				if published != test.wantPublished {
+					t.Fatalf("want published=%v got=%v", test.wantPublished, published)
+				}
+			},
+		)
+	}
+}
+
+func TestOperatorBuildRetryWithoutReader(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	_, err = newsReader.NewOperatorBuilder().
+		Publisher(&mock.Publisher{}).
+		Consumer(&mock.Consumer{}).
+		OnConflict(newsReader.RetryOnConflict).
+		Logger(zapper.Sugar()).
+		Build()
+	if err == nil {
+		t.Fatalf("want build error without reader")
+	}
+}
+
+func TestOperatorMetadata(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	c := &mock.Consumer{
+		ConsumeFn: func(c chan<- newsReader.Article) {
+			c <- newsReader.Article{
+				ID: "aa",
+				Meta: newsReader.Metadata{
+					EventID:       "evt",
+					CorrelationID: "run",
+					Processors:    map[string]string{"NER": "v1", "mockProcessor": "old"},
+				},
+				ProcessingErrors: []newsReader.ProcessingError{
+					{Processor: "Keywords", Error: "kept"},
+					{Processor: "mockProcessor", Error: "forgotten"},
+				},
+			}
+			close(c)
+		},
+	}
+	pr := &mock.Processor{
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			return a, nil
+		},
+	}
+
+	var got newsReader.Metadata
+	var gotErrs []newsReader.ProcessingError
+	pu := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			got = a.Meta
+			gotErrs = a.ProcessingErrors
+			return nil
+		},
+	}
+
+	opr, err := newsReader.NewOperatorBuilder().
+		Processors(pr).
+		Publisher(pu).
+		Consumer(c).
+		Logger(zapper.Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("could not get new operator")
+	}
+
+	err = opr.Run()
+	if err != nil {
+		t.Fatalf("want no error, got=%v", err)
+	}
+
+	if got.EventID != "evt" || got.CorrelationID != "run" {
+		t.Fatalf("want consumed metadata to be kept, got=%v", got)
+	}
+	if v := got.Processors[pr.Name()]; v != pr.Version() {
+		t.Fatalf("want processor version=%v, got=%v", pr.Version(), v)
+	}
+	// results of other processors are kept, e.g. when reprocessing a subset of processors
+	if v := got.Processors["NER"]; v != "v1" {
+		t.Fatalf("want version of other processor kept, got=%v", got.Processors)
+	}
+	if len(gotErrs) != 1 || gotErrs[0].Processor != "Keywords" {
+		t.Fatalf("want errors of other processors kept only, got=%v", gotErrs)
+	}
+}
+
+func TestOperatorBatchPublisher(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	tests := []struct {
+		Name     string
+		Failed   []string
+		wantErr  bool
+		wantMore string
+	}{
+		{
+			Name:    "pass",
+			wantErr: false,
+		},
+		{
+			Name:    "document failure",
+			Failed:  []string{"bb"},
+			wantErr: true,
+		},
+		{
+			Name:     "failures beyond the limit are counted",
+			Failed:   ids(150),
+			wantErr:  true,
+			wantMore: "50 more errors",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				c := &mock.Consumer{
+					ConsumeFn: func(c chan<- newsReader.Article) {
+						c <- newsReader.Article{ID: "aa"}
+						c <- newsReader.Article{ID: "bb"}
+						close(c)
+					},
+				}
+
+				failures := make(chan newsReader.PublishError, 2)
+				pu := &mock.BatchPublisher{
+					Publisher: mock.Publisher{
+						PublishFn: func(a newsReader.Article) error {
+							return nil
+						},
+					},
+					FailuresC: failures,
+					CloseFn: func() error {
+						for _, id := range test.Failed {
+							failures <- newsReader.PublishError{
+								Article: newsReader.Article{ID: id},
+								Err:     errors.New("some document error"),
+							}
+						}
+						close(failures)
+						return nil
+					},
+				}
+
+				opr, err := newsReader.NewOperatorBuilder().Publisher(pu).Consumer(c).Logger(zapper.Sugar()).Build()
+				if err != nil {
+					t.Fatalf("could not get new operator")
+				}
+
+				err = opr.Run()
+
+				if (err != nil) != test.wantErr {
+					t.Fatalf("wanted return error=%v got=%v", test.wantErr, err)
+				}
+				if len(test.wantMore) != 0 && !strings.Contains(err.Error(), test.wantMore) {
+					t.Errorf("want error with %q, got %v", test.wantMore, err)
+				}
+				if !pu.CloseInvoked {
+					t.Fatalf("want publisher to be closed")
+				}
+			},
+		)
+	}
+}
+
+func ids(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("article-%d", i)
+	}
+	return ids
+}
+
+func TestOperatorRunUntil(t *testing.T) {
+	stopped := make(chan struct{})
+	c := &mock.Consumer{
+		ConsumeFn: func(c chan<- newsReader.Article) {
+			c <- newsReader.Article{ID: "aa"}
+			// consumed before the consumer stopped
+			<-stopped
+			c <- newsReader.Article{ID: "bb"}
+			close(c)
+		},
+		StopFn: func() { close(stopped) },
+	}
+
+	var mu sync.Mutex
+	var published []string
+	first := make(chan struct{})
+	failures := make(chan newsReader.PublishError)
+	pu := &mock.BatchPublisher{
+		Publisher: mock.Publisher{
+			PublishFn: func(a newsReader.Article) error {
+				mu.Lock()
+				defer mu.Unlock()
+				published = append(published, a.ID)
+				if a.ID == "aa" {
+					close(first)
+				}
+				return nil
+			},
+		},
+		FailuresC: failures,
+		CloseFn: func() error {
+			close(failures)
+			return nil
+		},
+	}
+
+	opr, err := newsReader.NewOperatorBuilder().Publisher(pu).Consumer(c).Logger(zap.NewNop().Sugar()).Build()
+	if err != nil {
+		t.Fatalf("could not get new operator")
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- opr.RunUntil(stop)
+	}()
+
+	<-first
+	close(stop)
+	select {
+	case err = <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("RunUntil did not return after stop")
+	}
+
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(published) != 2 || !pu.CloseInvoked {
+		t.Errorf("want consumed articles published and publisher closed, got %v closed=%v", published, pu.CloseInvoked)
+	}
+}
+
+func TestOperatorProcessorDAG(t *testing.T) {
+	// summary and ner only proceed once both run, so they must run in parallel
+	started := make(chan struct{}, 2)
+	both := func() error {
+		started <- struct{}{}
+		deadline := time.After(time.Second)
+		for len(started) < 2 {
+			select {
+			case <-deadline:
+				return errors.New("processors did not run in parallel")
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return nil
+	}
+
+	summary := &mock.FieldProcessor{
+		ProcessorName: "summary",
+		ReadFields:    []newsReader.Field{newsReader.FieldBody},
+		WriteFields:   []newsReader.Field{newsReader.FieldSummary},
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			a.Summary = "summary"
+			// writes to undeclared fields are dropped
+			a.Pers = []string{"dropped"}
+			return a, both()
+		},
+	}
+	ner := &mock.FieldProcessor{
+		ProcessorName: "ner",
+		ReadFields:    []newsReader.Field{newsReader.FieldBody},
+		WriteFields:   []newsReader.Field{newsReader.FieldPers},
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			a.Pers = []string{"Merkel"}
+			return a, both()
+		},
+	}
+	story := &mock.FieldProcessor{
+		ProcessorName: "story",
+		ReadFields:    []newsReader.Field{newsReader.FieldPers},
+		WriteFields:   []newsReader.Field{newsReader.FieldStory},
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			if len(a.Pers) != 1 || a.Pers[0] != "Merkel" {
+				return a, fmt.Errorf("want pers of ner, got %v", a.Pers)
+			}
+			a.Story = "story-1"
+			return a, nil
+		},
+	}
+
+	var published []newsReader.Article
+	c := &mock.Consumer{
+		ConsumeFn: func(c chan<- newsReader.Article) {
+			c <- newsReader.Article{ID: "article-1", Body: "body"}
+			close(c)
+		},
+	}
+	pu := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			published = append(published, a)
+			return nil
+		},
+	}
+
+	opr, err := newsReader.NewOperatorBuilder().
+		Processors(summary, ner, story).
+		Consumer(c).
+		Publisher(pu).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	err = opr.Run()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("want 1 published article, got %v", len(published))
+	}
+	got := published[0]
+	if got.Summary != "summary" || got.Story != "story-1" || !reflect.DeepEqual(got.Pers, []string{"Merkel"}) {
+		t.Errorf("want merged outputs of all processors, got %+v", got)
+	}
+	want := map[string]string{"summary": "", "ner": "", "story": ""}
+	if !reflect.DeepEqual(got.Meta.Processors, want) {
+		t.Errorf("want processors=%v, got %v", want, got.Meta.Processors)
+	}
+}
+
+func TestOperatorBuilderConflict(t *testing.T) {
+	writer := func(name string) *mock.FieldProcessor {
+		return &mock.FieldProcessor{
+			ProcessorName: name,
+			ReadFields:    []newsReader.Field{newsReader.FieldBody},
+			WriteFields:   []newsReader.Field{newsReader.FieldSummary},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pp      []newsReader.Processor
+		wantErr bool
+	}{
+		{
+			name:    "independent writers",
+			pp:      []newsReader.Processor{writer("abstractive"), writer("extractive")},
+			wantErr: true,
+		},
+		{
+			name: "dependent writers",
+			pp: []newsReader.Processor{
+				writer("abstractive"),
+				&mock.FieldProcessor{
+					ProcessorName: "shorten",
+					ReadFields:    []newsReader.Field{newsReader.FieldSummary},
+					WriteFields:   []newsReader.Field{newsReader.FieldSummary},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "undeclared fields",
+			pp:      []newsReader.Processor{writer("abstractive"), &mock.Processor{}},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				_, err := newsReader.NewOperatorBuilder().
+					Processors(test.pp...).
+					Consumer(&mock.Consumer{}).
+					Publisher(&mock.Publisher{}).
+					Logger(zap.NewNop().Sugar()).
+					Build()
+				if (err != nil) != test.wantErr {
+					t.Errorf("want error=%v, got %v", test.wantErr, err)
+				}
+			},
+		)
+	}
+}
+
+func TestOperatorWhen(t *testing.T) {
+	var summarized []string
+	summary := &mock.FieldProcessor{
+		ProcessorName: "summary",
+		ReadFields:    []newsReader.Field{newsReader.FieldBody},
+		WriteFields:   []newsReader.Field{newsReader.FieldSummary},
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			summarized = append(summarized, a.ID)
+			a.Summary = "summary"
+			return a, nil
+		},
+	}
+
+	var published []newsReader.Article
+	c := &mock.Consumer{
+		ConsumeFn: func(c chan<- newsReader.Article) {
+			c <- newsReader.Article{ID: "short", Body: "kurz"}
+			c <- newsReader.Article{ID: "long", Body: "ein langer Text"}
+			close(c)
+		},
+	}
+	pu := &mock.Publisher{
+		PublishFn: func(a newsReader.Article) error {
+			published = append(published, a)
+			return nil
+		},
+	}
+
+	opr, err := newsReader.NewOperatorBuilder().
+		Processors(summary).
+		When("summary", newsReader.MinBodyLength(10)).
+		Consumer(c).
+		Publisher(pu).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	err = opr.Run()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(summarized, []string{"long"}) {
+		t.Errorf("want only long article summarized, got %v", summarized)
+	}
+	for _, a := range published {
+		_, processed := a.Meta.Processors["summary"]
+		if processed != (a.ID == "long") {
+			t.Errorf("want summary recorded=%v for article=%s, got %v", a.ID == "long", a.ID, a.Meta.Processors)
+		}
+	}
+
+	_, err = newsReader.NewOperatorBuilder().
+		Processors(summary).
+		When("ner", newsReader.MinBodyLength(10)).
+		Consumer(c).
+		Publisher(pu).
+		Logger(zap.NewNop().Sugar()).
+		Build()
+	if err == nil {
+		t.Errorf("want error for condition of unknown processor")
+	}
+}
+
+func TestOperatorFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     newsReader.FailurePolicy
+		failures   int
+		deadLetter bool
+
+		wantPublished int
+		wantDead      int
+		wantErrors    int
+		wantErr       bool
+	}{
+		{name: "optional", policy: newsReader.Optional, failures: 1, wantPublished: 1, wantErrors: 1},
+		{name: "required retried", policy: newsReader.Required, failures: 1, wantPublished: 1},
+		{name: "required dead-letter", policy: newsReader.Required, failures: 2, deadLetter: true, wantDead: 1, wantErrors: 1},
+		{name: "required dropped", policy: newsReader.Required, failures: 2, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				calls := 0
+				ner := &mock.FieldProcessor{
+					ProcessorName: "ner",
+					ReadFields:    []newsReader.Field{newsReader.FieldBody},
+					WriteFields:   []newsReader.Field{newsReader.FieldPers},
+					ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+						calls++
+						if calls <= test.failures {
+							return a, errors.New("torchServe unavailable")
+						}
+						a.Pers = []string{"Merkel"}
+						return a, nil
+					},
+				}
+				var stories int
+				story := &mock.FieldProcessor{
+					ProcessorName: "story",
+					ReadFields:    []newsReader.Field{newsReader.FieldPers},
+					WriteFields:   []newsReader.Field{newsReader.FieldStory},
+					ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+						stories++
+						return a, nil
+					},
+				}
+
+				var published, dead []newsReader.Article
+				c := &mock.Consumer{
+					ConsumeFn: func(c chan<- newsReader.Article) {
+						c <- newsReader.Article{ID: "article-1", Body: "body"}
+						close(c)
+					},
+				}
+				pu := &mock.Publisher{
+					PublishFn: func(a newsReader.Article) error {
+						published = append(published, a)
+						return nil
+					},
+				}
+
+				b := newsReader.NewOperatorBuilder().
+					Processors(ner, story).
+					OnFailure("ner", test.policy).
+					Retries(1, time.Millisecond).
+					Consumer(c).
+					Publisher(pu).
+					Logger(zap.NewNop().Sugar())
+				if test.deadLetter {
+					b.DeadLetter(
+						&mock.Publisher{
+							PublishFn: func(a newsReader.Article) error {
+								dead = append(dead, a)
+								return nil
+							},
+						},
+					)
+				}
+				opr, err := b.Build()
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+
+				err = opr.Run()
+				if (err != nil) != test.wantErr {
+					t.Fatalf("want error=%v, got %v", test.wantErr, err)
+				}
+				if len(published) != test.wantPublished {
+					t.Fatalf("want %v published, got %v", test.wantPublished, len(published))
+				}
+				if len(dead) != test.wantDead {
+					t.Fatalf("want %v dead-lettered, got %v", test.wantDead, len(dead))
+				}
+				for _, a := range append(published, dead...) {
+					if len(a.ProcessingErrors) != test.wantErrors {
+						t.Errorf("want %v processing errors, got %+v", test.wantErrors, a.ProcessingErrors)
+					}
+					if len(a.ProcessingErrors) != 0 && a.ProcessingErrors[0].Processor != "ner" {
+						t.Errorf("want error of ner, got %+v", a.ProcessingErrors[0])
+					}
+				}
+				if stories != test.wantPublished {
+					t.Errorf("want story processed only if the required ner succeeded, got %v calls", stories)
+				}
+			},
+		)
+	}
+}
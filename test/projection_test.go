@@ -0,0 +1,147 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/memory"
+	"newsReader/mock"
+)
+
+func TestProjectionRun(t *testing.T) {
+	collected := func(aa ...newsReader.Article) func(c chan<- newsReader.Article) {
+		return func(c chan<- newsReader.Article) {
+			for _, a := range aa {
+				c <- a
+			}
+			close(c)
+		}
+	}
+
+	tests := []struct {
+		Name string
+
+		Replayed     []newsReader.Article
+		ReplayErr    error
+		Collected    []newsReader.Article
+		Preprocessed []newsReader.Article
+
+		wantCurrent  newsReader.Article
+		wantVersions []uint64
+		wantErr      bool
+	}{
+		{
+			Name:         "collected and preprocessed",
+			Collected:    []newsReader.Article{{ID: "aa", Body: "b", Version: 1}},
+			Preprocessed: []newsReader.Article{{ID: "aa", Body: "b", Summary: "s", Version: 2}},
+			wantCurrent:  newsReader.Article{ID: "aa", Body: "b", Summary: "s", Version: 2},
+			wantVersions: []uint64{1, 2},
+		},
+		{
+			Name: "recollected unchanged body keeps enrichments",
+			Collected: []newsReader.Article{
+				{ID: "aa", Body: "b", Version: 1},
+				{ID: "aa", Body: "b", Title: "t", Version: 3},
+			},
+			Preprocessed: []newsReader.Article{{ID: "aa", Body: "b", Summary: "s", Version: 2}},
+			wantCurrent:  newsReader.Article{ID: "aa", Body: "b", Title: "t", Summary: "s", Version: 3},
+			wantVersions: []uint64{1, 2, 3},
+		},
+		{
+			Name: "duplicate event",
+			Collected: []newsReader.Article{
+				{ID: "aa", Body: "b", Version: 1},
+				{ID: "aa", Body: "b", Version: 1},
+			},
+			wantCurrent:  newsReader.Article{ID: "aa", Body: "b", Version: 1},
+			wantVersions: []uint64{1},
+		},
+		{
+			Name:         "replayed history",
+			Replayed:     []newsReader.Article{{ID: "aa", Body: "b", Version: 1}},
+			Preprocessed: []newsReader.Article{{ID: "aa", Body: "b", Summary: "s", Version: 2}},
+			wantCurrent:  newsReader.Article{ID: "aa", Body: "b", Summary: "s", Version: 2},
+			wantVersions: []uint64{1, 2},
+		},
+		{
+			Name:      "replay error",
+			ReplayErr: errors.New("some replay error"),
+			Collected: []newsReader.Article{{ID: "aa", Body: "b", Version: 1}},
+			wantErr:   true,
+		},
+		{
+			Name:      "missing id",
+			Collected: []newsReader.Article{{Body: "b", Version: 1}},
+			wantErr:   true,
+		},
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	logger := zapper.Sugar()
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				pb := newsReader.NewProjectionBuilder()
+				replay := func(c chan<- newsReader.Article) error {
+					collected(test.Replayed...)(c)
+					return test.ReplayErr
+				}
+				con := &mock.Consumer{ConsumeFn: collected(test.Collected...), ReplayFn: replay}
+				pr, err := pb.Consumer("collected", con).
+					Consumer("preprocessed", &mock.Consumer{ConsumeFn: collected(test.Preprocessed...)}).
+					Store(memory.NewStore()).
+					Logger(logger).
+					Build()
+				if err != nil {
+					t.Fatalf("could not get new projection")
+				}
+
+				err = pr.Run()
+				if (err != nil) != test.wantErr {
+					t.Fatalf("want error=%v, got=%v", test.wantErr, err)
+				}
+				if test.wantErr {
+					if test.ReplayErr != nil && con.ConsumeInvoked {
+						t.Fatalf("want no consume after failed replay")
+					}
+					return
+				}
+
+				got, err := pr.Article("aa")
+				if err != nil {
+					t.Fatalf("want article, got error=%v", err)
+				}
+				if !reflect.DeepEqual(got, test.wantCurrent) {
+					t.Fatalf("want current=%v, got=%v", test.wantCurrent, got)
+				}
+
+				ee, err := pr.Versions("aa")
+				if err != nil {
+					t.Fatalf("want versions, got error=%v", err)
+				}
+				var versions []uint64
+				for _, e := range ee {
+					versions = append(versions, e.Article.Version)
+				}
+				if !reflect.DeepEqual(versions, test.wantVersions) {
+					t.Fatalf("want versions=%v, got=%v", test.wantVersions, versions)
+				}
+
+				_, err = pr.Article("unknown")
+				if !errors.Is(err, newsReader.ErrNotFound) {
+					t.Fatalf("want ErrNotFound, got=%v", err)
+				}
+			},
+		)
+	}
+}
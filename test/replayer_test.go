@@ -0,0 +1,132 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestReplayerRun(t *testing.T) {
+	tests := []struct {
+		Name string
+
+		ReplayFn  func(c chan<- newsReader.Article) error
+		PublishFn func(a newsReader.Article) error
+
+		Latest bool
+
+		wantPublished []string
+		wantErr       bool
+	}{
+		{
+			Name: "pass",
+			ReplayFn: func(c chan<- newsReader.Article) error {
+				c <- newsReader.Article{ID: "aa", Title: "1"}
+				c <- newsReader.Article{ID: "bb", Title: "1"}
+				c <- newsReader.Article{ID: "aa", Title: "2"}
+				close(c)
+				return nil
+			},
+			PublishFn: func(a newsReader.Article) error {
+				return nil
+			},
+			wantPublished: []string{"aa1", "bb1", "aa2"},
+			wantErr:       false,
+		},
+		{
+			Name: "latest only",
+			ReplayFn: func(c chan<- newsReader.Article) error {
+				c <- newsReader.Article{ID: "aa", Title: "1"}
+				c <- newsReader.Article{ID: "bb", Title: "1"}
+				c <- newsReader.Article{ID: "aa", Title: "2"}
+				close(c)
+				return nil
+			},
+			PublishFn: func(a newsReader.Article) error {
+				return nil
+			},
+			Latest:        true,
+			wantPublished: []string{"aa2", "bb1"},
+			wantErr:       false,
+		},
+		{
+			Name: "source error",
+			ReplayFn: func(c chan<- newsReader.Article) error {
+				c <- newsReader.Article{ID: "aa", Title: "1"}
+				close(c)
+				return errors.New("some source error")
+			},
+			PublishFn: func(a newsReader.Article) error {
+				return nil
+			},
+			wantPublished: []string{"aa1"},
+			wantErr:       true,
+		},
+		{
+			Name: "publisher error",
+			ReplayFn: func(c chan<- newsReader.Article) error {
+				c <- newsReader.Article{ID: "aa", Title: "1"}
+				c <- newsReader.Article{ID: "bb", Title: "1"}
+				close(c)
+				return nil
+			},
+			PublishFn: func(a newsReader.Article) error {
+				if a.ID == "aa" {
+					return errors.New("some publisher error")
+				}
+				return nil
+			},
+			wantPublished: []string{"bb1"},
+			wantErr:       true,
+		},
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	logger := zapper.Sugar()
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				var published []string
+				s := &mock.Source{ReplayFn: test.ReplayFn}
+				p := &mock.Publisher{
+					PublishFn: func(a newsReader.Article) error {
+						err := test.PublishFn(a)
+						if err == nil {
+							published = append(published, a.ID+a.Title)
+						}
+						return err
+					},
+				}
+
+				rb := newsReader.NewReplayerBuilder()
+				r, err := rb.Source(s).Publisher(p).Rate(1000).LatestOnly(test.Latest).Logger(logger).Build()
+				if err != nil {
+					t.Fatalf("could not get new replayer")
+				}
+
+				n, err := r.Run()
+
+				if (err != nil) != test.wantErr {
+					t.Fatalf("want error=%v, got=%v", test.wantErr, err)
+				}
+				if n != len(test.wantPublished) {
+					t.Fatalf("want n=%v, got=%v", len(test.wantPublished), n)
+				}
+				if !reflect.DeepEqual(published, test.wantPublished) {
+					t.Fatalf("want published=%v, got=%v", test.wantPublished, published)
+				}
+			},
+		)
+	}
+}
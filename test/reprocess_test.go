@@ -0,0 +1,157 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestReplayConsumerConsume(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	replay := func(c chan<- newsReader.Article) error {
+		c <- newsReader.Article{ID: "aa", Title: "1", Url: "https://a.de/1", Version: 1, Meta: newsReader.Metadata{Recorded: t0}}
+		c <- newsReader.Article{ID: "bb", Title: "1", Url: "https://b.de/1", Version: 1, Meta: newsReader.Metadata{Recorded: t0.Add(time.Hour)}}
+		c <- newsReader.Article{ID: "aa", Title: "2", Url: "https://a.de/1", Version: 3, Meta: newsReader.Metadata{Recorded: t0.Add(2 * time.Hour)}}
+		close(c)
+		return nil
+	}
+	// recollected articles are recorded later than they have been collected
+	recollected := func(c chan<- newsReader.Article) error {
+		c <- newsReader.Article{ID: "aa", Title: "1", Version: 1, Collected: t0.Format(time.RFC3339), Meta: newsReader.Metadata{Recorded: t0.Add(3 * time.Hour)}}
+		c <- newsReader.Article{ID: "bb", Title: "1", Version: 1, Meta: newsReader.Metadata{Recorded: t0.Add(3 * time.Hour)}}
+		c <- newsReader.Article{ID: "cc", Title: "1", Version: 1, Collected: t0.Format(time.RFC3339), Meta: newsReader.Metadata{Recorded: t0}}
+		c <- newsReader.Article{ID: "cc", Title: "2", Version: 2, Collected: t0.Add(3 * time.Hour).Format(time.RFC3339), Meta: newsReader.Metadata{Recorded: t0.Add(3 * time.Hour)}}
+		c <- newsReader.Article{ID: "aa", Title: "2", Version: 2, Collected: t0.Add(time.Hour).Format(time.RFC3339), Meta: newsReader.Metadata{Recorded: t0.Add(4 * time.Hour)}}
+		close(c)
+		return nil
+	}
+
+	tests := []struct {
+		Name string
+
+		ReplayFn func(c chan<- newsReader.Article) error
+		LatestFn func(id string) (newsReader.Article, bool, error)
+		Filters  []newsReader.Filter
+
+		want    []string
+		wantErr bool
+	}{
+		{
+			Name:     "latest without filter",
+			ReplayFn: replay,
+			want:     []string{"aa2", "bb1"},
+		},
+		{
+			Name:     "between",
+			ReplayFn: replay,
+			Filters:  []newsReader.Filter{newsReader.Between(t0, t0.Add(2*time.Hour))},
+			want:     []string{"bb1"},
+		},
+		{
+			Name:     "open bound",
+			ReplayFn: replay,
+			Filters:  []newsReader.Filter{newsReader.Between(t0.Add(2*time.Hour), time.Time{})},
+			want:     []string{"aa2"},
+		},
+		{
+			Name:     "between collected",
+			ReplayFn: recollected,
+			Filters:  []newsReader.Filter{newsReader.Between(t0, t0.Add(2*time.Hour))},
+			want:     []string{"aa2"},
+		},
+		{
+			Name:     "between recorded without collected",
+			ReplayFn: recollected,
+			Filters:  []newsReader.Filter{newsReader.Between(t0.Add(2*time.Hour), time.Time{})},
+			want:     []string{"bb1", "cc2"},
+		},
+		{
+			Name:     "from host",
+			ReplayFn: replay,
+			Filters:  []newsReader.Filter{newsReader.FromHost("b.de")},
+			want:     []string{"bb1"},
+		},
+		{
+			Name: "source error",
+			ReplayFn: func(c chan<- newsReader.Article) error {
+				close(c)
+				return errors.New("some source error")
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			Name:     "latest preprocessed state",
+			ReplayFn: replay,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				if id == "aa" {
+					return newsReader.Article{ID: "aa", Title: "p", Version: 4}, true, nil
+				}
+				return newsReader.Article{ID: id, Title: "collected", Version: 1}, false, nil
+			},
+			want: []string{"aap", "bb1"},
+		},
+		{
+			Name:     "state error",
+			ReplayFn: replay,
+			LatestFn: func(id string) (newsReader.Article, bool, error) {
+				if id == "bb" {
+					return newsReader.Article{}, false, errors.New("some read error")
+				}
+				return newsReader.Article{}, false, nil
+			},
+			want:    []string{"aa2"},
+			wantErr: true,
+		},
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	zapper, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("could not init logger")
+	}
+
+	logger := zapper.Sugar()
+
+	for _, test := range tests {
+		t.Run(
+			test.Name, func(t *testing.T) {
+				s := &mock.Source{ReplayFn: test.ReplayFn}
+				var state newsReader.Reader
+				if test.LatestFn != nil {
+					state = &mock.Consumer{LatestFn: test.LatestFn}
+				}
+				rc := newsReader.NewReplayConsumer(s, state, logger, test.Filters...)
+
+				c := make(chan newsReader.Article)
+				go rc.Consume(c)
+
+				var got []string
+				correlation := make(map[string]bool)
+				for a := range c {
+					if a.Version != 0 {
+						t.Fatalf("want version reset, got=%v", a.Version)
+					}
+					correlation[a.Meta.CorrelationID] = true
+					got = append(got, a.ID+a.Title)
+				}
+
+				if !reflect.DeepEqual(got, test.want) {
+					t.Fatalf("want=%v, got=%v", test.want, got)
+				}
+				if err := rc.Err(); (err != nil) != test.wantErr {
+					t.Fatalf("want error=%v, got=%v", test.wantErr, err)
+				}
+				if len(got) != 0 && (len(correlation) != 1 || correlation[""]) {
+					t.Fatalf("want one correlationID per run, got=%v", correlation)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,123 @@
+package newsReader_test
+
+import (
+	"testing"
+	"time"
+
+	"newsReader"
+)
+
+func TestCronNext(t *testing.T) {
+	// a Monday
+	t0 := time.Date(2022, 1, 3, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{expr: "* * * * *", want: time.Date(2022, 1, 3, 10, 18, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2022, 1, 3, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 */2 * * *", want: time.Date(2022, 1, 3, 12, 0, 0, 0, time.UTC)},
+		{expr: "30 6-8 * * *", want: time.Date(2022, 1, 4, 6, 30, 0, 0, time.UTC)},
+		{expr: "0 9 * * 6,7", want: time.Date(2022, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 3 *", want: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week
+		{expr: "0 0 15 * 2", want: time.Date(2022, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+		{expr: "0 0 * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.expr, func(t *testing.T) {
+				c, err := newsReader.Cron(test.expr)
+				if (err != nil) != test.wantErr {
+					t.Fatalf("want error=%v, got %v", test.wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				if got := c.Next(t0); !got.Equal(test.want) {
+					t.Errorf("want next=%v, got %v", test.want, got)
+				}
+			},
+		)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	t0 := time.Date(2022, 1, 3, 21, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		timing string
+		from   int
+		to     int
+		jitter time.Duration
+		want   time.Time
+		max    time.Time
+	}{
+		{name: "always active", want: t0.Add(2 * time.Hour)},
+		{name: "within active hours", from: 20, to: 2, want: t0.Add(2 * time.Hour)},
+		{name: "after active hours", from: 6, to: 22, want: time.Date(2022, 1, 4, 6, 0, 0, 0, time.UTC)},
+		{name: "jitter", jitter: time.Minute, want: t0.Add(2 * time.Hour), max: t0.Add(2*time.Hour + time.Minute)},
+		{
+			name:   "cron after active hours",
+			timing: "30 */2 * * *",
+			from:   6,
+			to:     22,
+			want:   time.Date(2022, 1, 4, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "cron after active hours on odd hours",
+			timing: "0 1-23/2 * * *",
+			from:   6,
+			to:     22,
+			want:   time.Date(2022, 1, 4, 7, 0, 0, 0, time.UTC),
+		},
+		{name: "cron never active", timing: "0 3 * * *", from: 6, to: 22},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if len(test.timing) == 0 {
+					test.timing = "2h"
+				}
+				timing, err := newsReader.ParseTiming(test.timing)
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				s := newsReader.Schedule{
+					Timing:     timing,
+					Jitter:     test.jitter,
+					ActiveFrom: test.from,
+					ActiveTo:   test.to,
+					Location:   time.UTC,
+				}
+				got := s.Next(t0)
+				if test.max.IsZero() && !got.Equal(test.want) {
+					t.Errorf("want next=%v, got %v", test.want, got)
+				}
+				if !test.max.IsZero() && (got.Before(test.want) || !got.Before(test.max)) {
+					t.Errorf("want next in [%v, %v), got %v", test.want, test.max, got)
+				}
+			},
+		)
+	}
+}
+
+func TestParseHours(t *testing.T) {
+	from, to, err := newsReader.ParseHours("22-6")
+	if err != nil || from != 22 || to != 6 {
+		t.Errorf("want 22, 6, got %v, %v, %v", from, to, err)
+	}
+	for _, s := range []string{"6", "a-6", "6-25", "24-1"} {
+		_, _, err = newsReader.ParseHours(s)
+		if err == nil {
+			t.Errorf("want error for hours=%s", s)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func storyArticles(t0 time.Time) []newsReader.Article {
+	return []newsReader.Article{
+		{
+			ID:        "article-1",
+			Title:     "Bundestag beschließt Haushalt für das kommende Jahr",
+			Body:      "Der Bundestag hat den Haushalt beschlossen. Finanzminister Lindner verteidigte die Schuldenbremse.",
+			Url:       "https://www.tagesschau.de/inland/haushalt-101.html",
+			Collected: t0.Format(time.RFC3339),
+			Pers:      []string{"Lindner"},
+			Orgs:      []string{"Bundestag"},
+		},
+		{
+			ID:        "article-2",
+			Title:     "Haushalt im Bundestag beschlossen",
+			Body:      "Nach langer Debatte beschloss der Bundestag den Haushalt. Lindner verteidigte die Schuldenbremse erneut.",
+			Url:       "https://www.spiegel.de/politik/haushalt.html",
+			Collected: t0.Add(time.Hour).Format(time.RFC3339),
+			Pers:      []string{"Lindner"},
+			Orgs:      []string{"Bundestag"},
+		},
+		{
+			ID:        "article-3",
+			Title:     "Unwetter sorgt für Überschwemmungen in Bayern",
+			Body:      "Starkregen hat in Teilen Bayerns zu Überschwemmungen geführt. Die Feuerwehr war im Dauereinsatz.",
+			Url:       "https://www.tagesschau.de/inland/unwetter-105.html",
+			Collected: t0.Add(2 * time.Hour).Format(time.RFC3339),
+			Locs:      []string{"Bayern"},
+		},
+	}
+}
+
+func TestClustererProcess(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	var published []newsReader.Story
+	var types []string
+	pub := &mock.StoryPublisher{
+		PublishStoryFn: func(s newsReader.Story, eType string) error {
+			published = append(published, s)
+			types = append(types, eType)
+			return nil
+		},
+	}
+
+	c := newsReader.NewClusterer(pub, 0.3, 48*time.Hour, zap.NewNop().Sugar())
+	p := c.Publisher(&mock.Publisher{PublishFn: func(a newsReader.Article) error { return nil }})
+
+	var stories []string
+	for _, a := range storyArticles(t0) {
+		got, err := c.Process(a)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		if len(published) != len(stories) {
+			t.Fatalf("want story published after article, got %v", published)
+		}
+		err = p.Publish(got)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		stories = append(stories, got.Story)
+	}
+
+	want := []string{"story-1", "story-1", "story-3"}
+	if !reflect.DeepEqual(stories, want) {
+		t.Fatalf("want stories=%v, got %v", want, stories)
+	}
+	wantTypes := []string{newsReader.StoryCreated, newsReader.StoryUpdated, newsReader.StoryCreated}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Errorf("want event types=%v, got %v", wantTypes, types)
+	}
+
+	s := published[1]
+	if !reflect.DeepEqual(s.Sources, []string{"www.spiegel.de", "www.tagesschau.de"}) {
+		t.Errorf("want both sources, got %v", s.Sources)
+	}
+	if !reflect.DeepEqual(s.Articles, []string{"article-1", "article-2"}) {
+		t.Errorf("want both articles, got %v", s.Articles)
+	}
+	if !s.FirstSeen.Equal(t0) || !s.LastSeen.Equal(t0.Add(time.Hour)) {
+		t.Errorf("want story seen from %v to %v, got %v to %v", t0, t0.Add(time.Hour), s.FirstSeen, s.LastSeen)
+	}
+
+	// a follow-up after the window starts a new story
+	late := storyArticles(t0)[1]
+	late.ID = "article-4"
+	late.Collected = t0.Add(72 * time.Hour).Format(time.RFC3339)
+	got, err := c.Process(late)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if got.Story != "story-4" {
+		t.Errorf("want new story after window, got %s", got.Story)
+	}
+}
+
+func TestClustererStopwords(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	pub := &mock.StoryPublisher{PublishStoryFn: func(s newsReader.Story, eType string) error { return nil }}
+	c := newsReader.NewClusterer(pub, 0.3, 48*time.Hour, zap.NewNop().Sugar())
+
+	// both articles share nothing but stopwords
+	aa := []newsReader.Article{
+		{
+			ID:        "article-1",
+			Title:     "Und wieder ist es nicht mehr, was wir noch immer haben",
+			Body:      "Der Haushalt wird nach der Debatte über die Schuldenbremse von dem Bundestag beschlossen.",
+			Collected: t0.Format(time.RFC3339),
+		},
+		{
+			ID:        "article-2",
+			Title:     "Und wieder ist es nicht mehr, was wir noch immer haben",
+			Body:      "Das Unwetter hat nach dem Starkregen in Bayern zu Überschwemmungen geführt.",
+			Collected: t0.Add(time.Hour).Format(time.RFC3339),
+		},
+	}
+
+	var stories []string
+	for _, a := range aa {
+		got, err := c.Process(a)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		stories = append(stories, got.Story)
+	}
+	if stories[0] == stories[1] {
+		t.Errorf("want articles sharing stopwords only in separate stories, got %v", stories)
+	}
+}
+
+func TestClustererUnpublishedArticle(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	var published []newsReader.Story
+	pub := &mock.StoryPublisher{
+		PublishStoryFn: func(s newsReader.Story, eType string) error {
+			published = append(published, s)
+			return nil
+		},
+	}
+	c := newsReader.NewClusterer(pub, 0.3, 48*time.Hour, zap.NewNop().Sugar())
+	p := c.Publisher(
+		&mock.Publisher{
+			PublishFn: func(a newsReader.Article) error {
+				if a.ID == "article-1" {
+					return &newsReader.WrongExpectedVersionError{StreamID: a.ID}
+				}
+				return nil
+			},
+		},
+	)
+
+	for _, a := range storyArticles(t0)[:2] {
+		got, err := c.Process(a)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		_ = p.Publish(got)
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("want story of the published article only, got %v", published)
+	}
+	if !reflect.DeepEqual(published[0].Articles, []string{"article-2"}) {
+		t.Errorf("want published articles only, got %v", published[0].Articles)
+	}
+	if !reflect.DeepEqual(published[0].Sources, []string{"www.spiegel.de"}) {
+		t.Errorf("want sources of published articles only, got %v", published[0].Sources)
+	}
+}
+
+func TestClustererPublishError(t *testing.T) {
+	var types []string
+	pub := &mock.StoryPublisher{
+		PublishStoryFn: func(s newsReader.Story, eType string) error {
+			if len(types) == 0 {
+				types = append(types, "failed")
+				return errors.New("test error")
+			}
+			types = append(types, eType)
+			return nil
+		},
+	}
+
+	c := newsReader.NewClusterer(pub, 0.3, 48*time.Hour, zap.NewNop().Sugar())
+	p := c.Publisher(&mock.Publisher{PublishFn: func(a newsReader.Article) error { return nil }})
+
+	for _, a := range storyArticles(time.Now())[:2] {
+		got, err := c.Process(a)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+		// the article has been published, a failed story is published with its next article
+		err = p.Publish(got)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+
+	want := []string{"failed", newsReader.StoryCreated}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("want event types=%v, got %v", want, types)
+	}
+}
+
+func TestClustererWarm(t *testing.T) {
+	t0 := time.Now().UTC().Add(-time.Hour)
+	aa := storyArticles(t0)
+	aa[0].Story = "story-old"
+
+	src := &mock.Source{
+		ReplayFn: func(c chan<- newsReader.Article) error {
+			c <- aa[0]
+			c <- aa[2]
+			close(c)
+			return nil
+		},
+	}
+	pub := &mock.StoryPublisher{
+		PublishStoryFn: func(s newsReader.Story, eType string) error {
+			return nil
+		},
+	}
+
+	c := newsReader.NewClusterer(pub, 0.3, 48*time.Hour, zap.NewNop().Sugar())
+	err := c.Warm(src)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	got, err := c.Process(aa[1])
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if got.Story != "story-old" {
+		t.Errorf("want warmed story-old, got %s", got.Story)
+	}
+}
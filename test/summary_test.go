@@ -0,0 +1,149 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestSentences(t *testing.T) {
+	text := "Am 3. Oktober feiert Deutschland z.B. den Tag der Einheit. Prof. Müller sprach in Berlin! " +
+		"Kommt A. Schmidt am 19.10. wieder?\nEin neuer Absatz"
+
+	want := []string{
+		"Am 3. Oktober feiert Deutschland z.B. den Tag der Einheit.",
+		"Prof. Müller sprach in Berlin!",
+		"Kommt A. Schmidt am 19.10. wieder?",
+		"Ein neuer Absatz",
+	}
+	got := newsReader.Sentences(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestTextRankProcess(t *testing.T) {
+	body := "Die Europäische Zentralbank erhöht den Leitzins auf vier Prozent. " +
+		"Das Wetter in Hamburg bleibt regnerisch. " +
+		"Der Leitzins der Zentralbank steigt damit zum zehnten Mal in Folge. " +
+		"Ökonomen erwarten, dass die Zentralbank den Leitzins im Herbst erneut erhöht."
+
+	tr := newsReader.NewTextRank(2, zap.NewNop().Sugar())
+	got, err := tr.Process(newsReader.Article{ID: "article-1", Body: body})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	want := "Die Europäische Zentralbank erhöht den Leitzins auf vier Prozent. " +
+		"Ökonomen erwarten, dass die Zentralbank den Leitzins im Herbst erneut erhöht."
+	if got.Summary != want {
+		t.Errorf("want summary=%q, got %q", want, got.Summary)
+	}
+	if got.SummaryMethod != newsReader.ExtractiveSummary {
+		t.Errorf("want method=%s, got %s", newsReader.ExtractiveSummary, got.SummaryMethod)
+	}
+
+	_, err = tr.Process(newsReader.Article{ID: "article-2"})
+	if err == nil {
+		t.Errorf("want error for empty body")
+	}
+}
+
+func TestFallbackProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		primary     func(a newsReader.Article) (newsReader.Article, error)
+		want        string
+		wantVersion string
+	}{
+		{
+			name: "primary succeeds",
+			primary: func(a newsReader.Article) (newsReader.Article, error) {
+				a.Summary = "abstract"
+				a.SummaryMethod = newsReader.AbstractiveSummary
+				return a, nil
+			},
+			want:        newsReader.AbstractiveSummary,
+			wantVersion: "mockVersion",
+		},
+		{
+			name: "primary fails",
+			primary: func(a newsReader.Article) (newsReader.Article, error) {
+				return newsReader.Article{}, errors.New("torchServe unavailable")
+			},
+			want:        newsReader.ExtractiveSummary,
+			wantVersion: "textrank-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				primary := &mock.Processor{ProcessFn: test.primary}
+				f := newsReader.NewFallback(zap.NewNop().Sugar(), primary, newsReader.NewTextRank(1, zap.NewNop().Sugar()))
+
+				got, err := f.Process(newsReader.Article{ID: "article-1", Body: "Ein Satz. Noch ein Satz."})
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				if got.SummaryMethod != test.want {
+					t.Errorf("want method=%s, got %s", test.want, got.SummaryMethod)
+				}
+				if got.ID != "article-1" {
+					t.Errorf("want article of the succeeding processor, got %+v", got)
+				}
+				if f.Version() != "mockVersion|textrank-1" {
+					t.Errorf("want version=mockVersion|textrank-1, got %s", f.Version())
+				}
+
+				// operators record the version of the processor which produced the summary
+				var published newsReader.Article
+				opr, err := newsReader.NewOperatorBuilder().
+					Processors(f).
+					Consumer(
+						&mock.Consumer{
+							ConsumeFn: func(c chan<- newsReader.Article) {
+								c <- newsReader.Article{ID: "article-1", Body: "Ein Satz. Noch ein Satz."}
+								close(c)
+							},
+						},
+					).
+					Publisher(
+						&mock.Publisher{
+							PublishFn: func(a newsReader.Article) error {
+								published = a
+								return nil
+							},
+						},
+					).
+					Logger(zap.NewNop().Sugar()).
+					Build()
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				err = opr.Run()
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				if v := published.Meta.Processors[f.Name()]; v != test.wantVersion {
+					t.Errorf("want recorded version=%s, got %s", test.wantVersion, v)
+				}
+			},
+		)
+	}
+
+	failing := &mock.Processor{
+		ProcessFn: func(a newsReader.Article) (newsReader.Article, error) {
+			return a, errors.New("failed")
+		},
+	}
+	_, err := newsReader.NewFallback(zap.NewNop().Sugar(), failing, newsReader.NewTextRank(1, zap.NewNop().Sugar())).
+		Process(newsReader.Article{ID: "article-2"})
+	if err == nil {
+		t.Errorf("want error if all processors fail")
+	}
+}
@@ -0,0 +1,75 @@
+package newsReader_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"newsReader"
+	"newsReader/mock"
+)
+
+func TestWarm(t *testing.T) {
+	tests := []struct {
+		name      string
+		restoreFn func(a newsReader.Article) error
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "latest articles",
+			restoreFn: func(a newsReader.Article) error { return nil },
+			want:      []string{"aa2", "bb1"},
+		},
+		{
+			name: "restore error",
+			restoreFn: func(a newsReader.Article) error {
+				return errors.New("some restore error")
+			},
+			want:    []string{"aa2"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				src := &mock.Source{
+					ReplayFn: func(c chan<- newsReader.Article) error {
+						c <- newsReader.Article{ID: "aa", Title: "1"}
+						c <- newsReader.Article{ID: "bb", Title: "1"}
+						c <- newsReader.Article{ID: "aa", Title: "2"}
+						close(c)
+						return nil
+					},
+				}
+
+				var got, other []string
+				w := &mock.Warmer{
+					RestoreFn: func(a newsReader.Article) error {
+						got = append(got, a.ID+a.Title)
+						return tt.restoreFn(a)
+					},
+				}
+				o := &mock.Warmer{
+					RestoreFn: func(a newsReader.Article) error {
+						other = append(other, a.ID+a.Title)
+						return nil
+					},
+				}
+
+				err := newsReader.Warm(src, zap.NewNop().Sugar(), w, o)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("want error=%v, got %v", tt.wantErr, err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("want restored=%v, got %v", tt.want, got)
+				}
+				if !tt.wantErr && !reflect.DeepEqual(other, tt.want) {
+					t.Errorf("want all warmers restored from one replay, got %v", other)
+				}
+			},
+		)
+	}
+}
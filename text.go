@@ -0,0 +1,24 @@
+package newsReader
+
+import (
+	"strings"
+	"unicode"
+)
+
+// words splits s into lower case words of letters and numbers.
+func words(s string) []string {
+	ww := fields(s)
+	for i, w := range ww {
+		ww[i] = strings.ToLower(w)
+	}
+	return ww
+}
+
+// fields splits s into words of letters and numbers.
+func fields(s string) []string {
+	return strings.FieldsFunc(
+		s, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		},
+	)
+}
@@ -15,6 +15,7 @@ import (
 
 type NER struct {
 	url     *url.URL
+	version string
 	log     *zap.SugaredLogger
 	maxLen  int
 	timeout time.Duration
@@ -25,19 +26,36 @@ type response struct {
 	Pred  string `json:"pred"`
 }
 
-func NewNER(addr string, l *zap.SugaredLogger, timeout time.Duration) (*NER, error) {
-	u, err := url.Parse(fmt.Sprintf("http://%s/predictions/ner", addr))
+// NewNER returns a NER using the given model version. The version is mandatory, it is recorded with
+// the entities of every article and keys the cached results.
+func NewNER(addr, version string, l *zap.SugaredLogger, timeout time.Duration) (*NER, error) {
+	if len(version) == 0 {
+		return nil, errors.New("no model version provided")
+	}
+	u, err := url.Parse(predictions(addr, "ner", version))
 	if err != nil {
 		return nil, err
 	}
 
-	return &NER{url: u, log: l, maxLen: 512, timeout: timeout}, nil
+	return &NER{url: u, version: version, log: l, maxLen: 512, timeout: timeout}, nil
 }
 
 func (n NER) Name() string {
 	return "NER"
 }
 
+func (n NER) Version() string {
+	return n.version
+}
+
+func (n NER) Reads() []newsReader.Field {
+	return []newsReader.Field{newsReader.FieldBody}
+}
+
+func (n NER) Writes() []newsReader.Field {
+	return []newsReader.Field{newsReader.FieldPers, newsReader.FieldLocs, newsReader.FieldOrgs}
+}
+
 func (n NER) Process(a newsReader.Article) (newsReader.Article, error) {
 	n.log.Infow("NER for article", "method", "Process", "articleID", a.ID)
 
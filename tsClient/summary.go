@@ -2,7 +2,7 @@ package tsClient
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/url"
 	"strings"
 	"time"
@@ -13,23 +13,41 @@ import (
 
 type Summary struct {
 	url     *url.URL
+	version string
 	timeout time.Duration
 	log     *zap.SugaredLogger
 }
 
-func NewSummary(addr string, l *zap.SugaredLogger, timeout time.Duration) (*Summary, error) {
-	u, err := url.Parse(fmt.Sprintf("http://%v/predictions/summarization", addr))
+// NewSummary returns a Summary using the given model version. The version is mandatory, it is recorded
+// with every summary and keys the cached results.
+func NewSummary(addr, version string, l *zap.SugaredLogger, timeout time.Duration) (*Summary, error) {
+	if len(version) == 0 {
+		return nil, errors.New("no model version provided")
+	}
+	u, err := url.Parse(predictions(addr, "summarization", version))
 	if err != nil {
 		return nil, err
 	}
 
-	return &Summary{url: u, log: l, timeout: timeout}, nil
+	return &Summary{url: u, version: version, log: l, timeout: timeout}, nil
 }
 
 func (s Summary) Name() string {
 	return "Summary"
 }
 
+func (s Summary) Version() string {
+	return s.version
+}
+
+func (s Summary) Reads() []newsReader.Field {
+	return []newsReader.Field{newsReader.FieldBody}
+}
+
+func (s Summary) Writes() []newsReader.Field {
+	return []newsReader.Field{newsReader.FieldSummary, newsReader.FieldSummaryMethod}
+}
+
 func (s Summary) Process(a newsReader.Article) (newsReader.Article, error) {
 	s.log.Infow("summarize article", "method", "Process", "articleID", a.ID)
 	bytes, err := post(s.url, strings.NewReader(a.Body), s.timeout)
@@ -47,5 +65,6 @@ func (s Summary) Process(a newsReader.Article) (newsReader.Article, error) {
 	}
 
 	a.Summary = r.Summary
+	a.SummaryMethod = newsReader.AbstractiveSummary
 	return a, nil
 }
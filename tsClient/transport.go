@@ -8,6 +8,11 @@ import (
 	"time"
 )
 
+// predictions returns the torchServe inference url of version of model.
+func predictions(addr, model, version string) string {
+	return fmt.Sprintf("http://%s/predictions/%s/%s", addr, model, version)
+}
+
 func post(u *url.URL, r io.Reader, t time.Duration) ([]byte, error) {
 	req, err := http.NewRequest("POST", u.String(), r)
 	if err != nil {
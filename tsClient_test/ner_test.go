@@ -32,6 +32,7 @@ func TestNERProcess(t *testing.T) {
 	tests := []struct {
 		name     string
 		arg      newsReader.Article
+		version  string
 		timeout  time.Duration
 		want     newsReader.Article
 		wantErr  bool
@@ -40,10 +41,14 @@ func TestNERProcess(t *testing.T) {
 		{
 			name:    "pass",
 			arg:     newsReader.Article{Body: body},
+			version: "1.0",
 			timeout: time.Second,
 			want:    newsReader.Article{Body: body, Pers: pers, Locs: locs, Orgs: orgs},
 			wantErr: false,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/predictions/ner/1.0" {
+					t.Fatalf("want versioned path, got %s", r.URL.Path)
+				}
 				cType := r.Header.Get("Content-Type")
 				if !strings.Contains(cType, "text/plain") {
 					t.Fatalf("want Content-Type contains text/plain")
@@ -92,9 +97,27 @@ func TestNERProcess(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "versioned model",
+			arg:     newsReader.Article{Body: body},
+			version: "2.0",
+			timeout: time.Second,
+			want:    newsReader.Article{Body: body, Pers: pers, Locs: []string{}, Orgs: []string{}},
+			wantErr: false,
+			tsServer: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/predictions/ner/2.0" {
+					t.Fatalf("want versioned path, got %s", r.URL.Path)
+				}
+				_, err := fmt.Fprint(w, `[{"token": "per", "pred": "B-PER"}]`)
+				if err != nil {
+					t.Fatalf("could not write to responseWriter")
+				}
+			},
+		},
 		{
 			name:    "header does not match",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Second,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +136,7 @@ func TestNERProcess(t *testing.T) {
 		{
 			name:    "server error",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Second,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +150,7 @@ func TestNERProcess(t *testing.T) {
 		{
 			name:    "invalid response",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Second,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -138,6 +163,7 @@ func TestNERProcess(t *testing.T) {
 		{
 			name:    "body length truncated",
 			arg:     newsReader.Article{Body: invalidBodyLen},
+			version: "1.0",
 			timeout: time.Second,
 			want: newsReader.Article{
 				Body: invalidBodyLen,
@@ -178,6 +204,7 @@ func TestNERProcess(t *testing.T) {
 		{
 			name:    "timeout error",
 			arg:     newsReader.Article{Body: body},
+			version: "1.0",
 			timeout: time.Millisecond,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -206,7 +233,7 @@ func TestNERProcess(t *testing.T) {
 					t.Fatalf("could not parse url")
 				}
 
-				ner, err := tsClient.NewNER(u.Host, logger, test.timeout)
+				ner, err := tsClient.NewNER(u.Host, test.version, logger, test.timeout)
 				if err != nil {
 					t.Fatalf("could not create new NER")
 					return
@@ -225,3 +252,10 @@ func TestNERProcess(t *testing.T) {
 		)
 	}
 }
+
+func TestNewNERWithoutVersion(t *testing.T) {
+	_, err := tsClient.NewNER("localhost:8080", "", zap.NewNop().Sugar(), time.Second)
+	if err == nil {
+		t.Errorf("want error for NER without model version")
+	}
+}
@@ -26,6 +26,7 @@ func TestSummaryProcess(t *testing.T) {
 	tests := []struct {
 		name     string
 		arg      newsReader.Article
+		version  string
 		timeout  time.Duration
 		want     newsReader.Article
 		wantErr  bool
@@ -34,8 +35,9 @@ func TestSummaryProcess(t *testing.T) {
 		{
 			name:    "pass",
 			arg:     newsReader.Article{Body: body},
+			version: "1.0",
 			timeout: time.Second,
-			want:    newsReader.Article{Body: body, Summary: summary},
+			want:    newsReader.Article{Body: body, Summary: summary, SummaryMethod: newsReader.AbstractiveSummary},
 			wantErr: false,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
 				cType := r.Header.Get("Content-Type")
@@ -68,9 +70,27 @@ func TestSummaryProcess(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "versioned model",
+			arg:     newsReader.Article{Body: body},
+			version: "2.0",
+			timeout: time.Second,
+			want:    newsReader.Article{Body: body, Summary: summary, SummaryMethod: newsReader.AbstractiveSummary},
+			wantErr: false,
+			tsServer: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/predictions/summarization/2.0" {
+					t.Fatalf("want versioned path, got %s", r.URL.Path)
+				}
+				_, err := fmt.Fprintf(w, `{"summary": "%s"}`, summary)
+				if err != nil {
+					t.Fatalf("could not write response")
+				}
+			},
+		},
 		{
 			name:    "header does not match",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Second,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -89,6 +109,7 @@ func TestSummaryProcess(t *testing.T) {
 		{
 			name:    "server error",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Second,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -102,6 +123,7 @@ func TestSummaryProcess(t *testing.T) {
 		{
 			name:    "timeout error",
 			arg:     newsReader.Article{},
+			version: "1.0",
 			timeout: time.Millisecond,
 			wantErr: true,
 			tsServer: func(w http.ResponseWriter, r *http.Request) {
@@ -129,7 +151,7 @@ func TestSummaryProcess(t *testing.T) {
 					t.Fatalf("could not parse addr")
 				}
 
-				summary, err := tsClient.NewSummary(u.Host, logger, test.timeout)
+				summary, err := tsClient.NewSummary(u.Host, test.version, logger, test.timeout)
 				if err != nil {
 					t.Fatalf("could not create new summary")
 					return
@@ -149,3 +171,10 @@ func TestSummaryProcess(t *testing.T) {
 		)
 	}
 }
+
+func TestNewSummaryWithoutVersion(t *testing.T) {
+	_, err := tsClient.NewSummary("localhost:8080", "", zap.NewNop().Sugar(), time.Second)
+	if err == nil {
+		t.Errorf("want error for summary without model version")
+	}
+}
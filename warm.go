@@ -0,0 +1,50 @@
+package newsReader
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Warmer is a Processor restoring its in-memory state from past articles, e.g. after a restart.
+type Warmer interface {
+	Restore(a Article) error
+}
+
+// Warm replays src once and restores all warmers from the latest article of every article stream,
+// so processors warmed from the same source share one replay.
+func Warm(src Source, l *zap.SugaredLogger, ww ...Warmer) error {
+	articles := make(chan Article)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- src.Replay(articles)
+	}()
+
+	n := 0
+	var restoreErr error
+	for a := range latestOnly(articles) {
+		// the replay is drained to its end on errors
+		if restoreErr != nil {
+			continue
+		}
+		for _, w := range ww {
+			err := w.Restore(a)
+			if err != nil {
+				restoreErr = fmt.Errorf("could not restore article=%s, %w", a.ID, err)
+				break
+			}
+		}
+		n++
+	}
+
+	err := <-errC
+	if err != nil {
+		return fmt.Errorf("could not replay source, %w", err)
+	}
+	if restoreErr != nil {
+		return restoreErr
+	}
+
+	l.Infow("warmed processors", "method", "Warm", "numArticles", n, "numProcessors", len(ww))
+	return nil
+}